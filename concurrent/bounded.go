@@ -0,0 +1,181 @@
+package concurrent
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CacheStats is a snapshot of the counters of a BoundedCache.
+type CacheStats struct {
+	Hits      uint64 // Get calls that found the key
+	Misses    uint64 // Get calls that did not find the key
+	Evictions uint64 // Entries evicted to make room
+}
+
+// BoundedCache is a concurrent cache that holds at most a fixed number of
+// entries. When it is full, adding a key evicts the key chosen by its
+// EvictionPolicy.
+//
+// Like Map, it is guarded by a read-write mutex. Get updates the policy and
+// takes the write lock; Peek, Len and Keys only take the read lock.
+type BoundedCache[K comparable, V any] struct {
+	mu       sync.RWMutex
+	m        map[K]V
+	capacity int
+	policy   EvictionPolicy[K]
+	stats    CacheStats
+	onEvict  atomic.Pointer[func(K, V, EvictReason)]
+}
+
+// NewBoundedCache creates a cache that holds at most capacity entries,
+// evicting according to policy. If policy is nil, NewLRU is used.
+// If capacity is less than one, the cache holds a single entry.
+func NewBoundedCache[K comparable, V any](capacity int, policy EvictionPolicy[K]) *BoundedCache[K, V] {
+	if policy == nil {
+		policy = NewLRU[K]()
+	}
+	capacity = max(capacity, 1)
+	return &BoundedCache[K, V]{
+		m:        make(map[K]V, capacity),
+		capacity: capacity,
+		policy:   policy,
+	}
+}
+
+// OnEvict registers fn to be called after an entry is evicted or deleted.
+// It replaces any previously registered callback.
+// fn is called without any lock held and may use the cache.
+func (c *BoundedCache[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.onEvict.Store(&fn)
+}
+
+func (c *BoundedCache[K, V]) evicted(key K, value V, reason EvictReason) {
+	if fn := c.onEvict.Load(); fn != nil {
+		(*fn)(key, value, reason)
+	}
+}
+
+// Get returns the value associated with the given key and records the access
+// with the eviction policy.
+// If the key is not found, Get returns the zero value for the value type and false.
+func (c *BoundedCache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	value, ok = c.m[key]
+	if ok {
+		c.stats.Hits++
+		c.policy.Access(key)
+	} else {
+		c.stats.Misses++
+	}
+	c.mu.Unlock()
+	return
+}
+
+// Peek is like Get, but neither affects which key is evicted next nor
+// counts as a hit or miss.
+func (c *BoundedCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.RLock()
+	value, ok = c.m[key]
+	c.mu.RUnlock()
+	return
+}
+
+// Contains reports whether the key is in the cache, without affecting
+// which key is evicted next.
+func (c *BoundedCache[K, V]) Contains(key K) bool {
+	_, ok := c.Peek(key)
+	return ok
+}
+
+// Set sets the given value to the given key in the cache.
+// If the key is new and the cache is full, another key is evicted first.
+// It reports whether an entry was evicted.
+func (c *BoundedCache[K, V]) Set(key K, value V) (evicted bool) {
+	var (
+		victim      K
+		victimValue V
+	)
+
+	c.mu.Lock()
+	if _, ok := c.m[key]; ok {
+		c.m[key] = value
+		c.policy.Access(key)
+		c.mu.Unlock()
+		return false
+	}
+
+	if len(c.m) >= c.capacity {
+		if k, ok := c.policy.Victim(); ok {
+			victim, victimValue, evicted = k, c.m[k], true
+			delete(c.m, k)
+			c.policy.Evict(k)
+			c.stats.Evictions++
+		}
+	}
+	c.m[key] = value
+	c.policy.Add(key)
+	c.mu.Unlock()
+
+	if evicted {
+		c.evicted(victim, victimValue, EvictCapacity)
+	}
+	return evicted
+}
+
+// Delete deletes the item with the given key from the cache.
+func (c *BoundedCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	value, ok := c.m[key]
+	if ok {
+		delete(c.m, key)
+		c.policy.Remove(key)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.evicted(key, value, EvictDeleted)
+	}
+}
+
+// Len returns the number of items in the cache.
+func (c *BoundedCache[K, V]) Len() int {
+	c.mu.RLock()
+	length := len(c.m)
+	c.mu.RUnlock()
+	return length
+}
+
+// Cap returns the maximum number of items the cache holds.
+func (c *BoundedCache[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Keys returns all keys in the cache.
+func (c *BoundedCache[K, V]) Keys() []K {
+	c.mu.RLock()
+	keys := make([]K, 0, len(c.m))
+	for k := range c.m {
+		keys = append(keys, k)
+	}
+	c.mu.RUnlock()
+	return keys
+}
+
+// Clear removes all items from the cache without calling the eviction callback.
+// The counters are kept.
+func (c *BoundedCache[K, V]) Clear() {
+	c.mu.Lock()
+	for k := range c.m {
+		c.policy.Remove(k)
+	}
+	clear(c.m)
+	c.mu.Unlock()
+}
+
+// Stats returns a snapshot of the cache's counters.
+func (c *BoundedCache[K, V]) Stats() CacheStats {
+	c.mu.RLock()
+	stats := c.stats
+	c.mu.RUnlock()
+	return stats
+}
@@ -0,0 +1,204 @@
+package concurrent_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+// sortedKeys returns the keys of c in ascending order.
+func sortedKeys(c *concurrent.BoundedCache[int, int]) []int {
+	keys := c.Keys()
+	slices.Sort(keys)
+	return keys
+}
+
+func TestBoundedCacheLRU(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](2, concurrent.NewLRU[int]())
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(1)
+	if !c.Set(3, 3) {
+		t.Error("want Set to report an eviction")
+	}
+
+	if keys := sortedKeys(c); !slices.Equal(keys, []int{1, 3}) {
+		t.Errorf("want [1 3], got %v", keys)
+	}
+}
+
+func TestBoundedCacheFIFO(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](2, concurrent.NewFIFO[int]())
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(1)
+	c.Set(1, 10)
+	c.Set(3, 3)
+
+	if keys := sortedKeys(c); !slices.Equal(keys, []int{2, 3}) {
+		t.Errorf("want [2 3], got %v", keys)
+	}
+}
+
+func TestBoundedCacheLFU(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](3, concurrent.NewLFU[int]())
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(1)
+	c.Get(1)
+	c.Get(3)
+	c.Set(4, 4) // evicts 2, used once
+
+	if keys := sortedKeys(c); !slices.Equal(keys, []int{1, 3, 4}) {
+		t.Errorf("want [1 3 4], got %v", keys)
+	}
+
+	c.Get(4)
+	c.Set(5, 5) // 3 and 4 are tied; 3 was used less recently
+
+	if keys := sortedKeys(c); !slices.Equal(keys, []int{1, 4, 5}) {
+		t.Errorf("want [1 4 5], got %v", keys)
+	}
+}
+
+func TestBoundedCache2QScanResistance(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](8, concurrent.New2Q[int]())
+
+	// Make keys 0-3 hot: seen, pushed out by a few new keys, then seen again.
+	for i := range 8 {
+		c.Set(i, i)
+	}
+	for i := 8; i < 12; i++ {
+		c.Set(i, i)
+	}
+	for i := range 4 {
+		c.Set(i, i)
+	}
+
+	// A long scan of keys seen once must not push out the hot keys.
+	for i := 100; i < 200; i++ {
+		c.Set(i, i)
+	}
+
+	for i := range 4 {
+		if !c.Contains(i) {
+			t.Errorf("want hot key %d to survive the scan", i)
+		}
+	}
+	if c.Len() != 8 {
+		t.Errorf("want 8 items, got %d", c.Len())
+	}
+}
+
+func TestBoundedCache2QDeleteIsNotEviction(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](8, concurrent.New2Q[int]())
+
+	// Keys deleted and set again are new keys, not keys seen again.
+	for i := range 8 {
+		c.Set(i, i)
+	}
+	for i := range 4 {
+		c.Delete(i)
+		c.Set(i, i)
+	}
+
+	for i := 100; i < 200; i++ {
+		c.Set(i, i)
+	}
+
+	for i := range 4 {
+		if c.Contains(i) {
+			t.Errorf("want key %d not to be promoted by Delete then Set", i)
+		}
+	}
+}
+
+func TestBoundedCachePeek(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](2, nil)
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	if v, ok := c.Peek(1); !ok || v != 1 {
+		t.Errorf("want 1 true, got %d %v", v, ok)
+	}
+	c.Set(3, 3) // Peek did not make 1 recently used
+
+	if c.Contains(1) {
+		t.Error("want key 1 to be evicted")
+	}
+	if stats := c.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("want Peek not to be counted, got %+v", stats)
+	}
+}
+
+func TestBoundedCacheStatsAndOnEvict(t *testing.T) {
+	c := concurrent.NewBoundedCache[int, int](1, nil)
+
+	var reasons []concurrent.EvictReason
+	c.OnEvict(func(key, value int, reason concurrent.EvictReason) {
+		if key != value {
+			t.Errorf("want value %d, got %d", key, value)
+		}
+		reasons = append(reasons, reason)
+	})
+
+	c.Set(1, 1)
+	c.Get(1)
+	c.Get(2)
+	c.Set(2, 2)
+	c.Delete(2)
+	c.Delete(2)
+
+	want := concurrent.CacheStats{Hits: 1, Misses: 1, Evictions: 1}
+	if stats := c.Stats(); stats != want {
+		t.Errorf("want %+v, got %+v", want, stats)
+	}
+	if !slices.Equal(reasons, []concurrent.EvictReason{concurrent.EvictCapacity, concurrent.EvictDeleted}) {
+		t.Errorf("want [capacity deleted], got %v", reasons)
+	}
+	if c.Len() != 0 || c.Cap() != 1 {
+		t.Errorf("want len 0 cap 1, got %d %d", c.Len(), c.Cap())
+	}
+}
+
+func TestBoundedCacheConcurrent(t *testing.T) {
+	policies := map[string]func() concurrent.EvictionPolicy[int]{
+		"LRU":  concurrent.NewLRU[int],
+		"LFU":  concurrent.NewLFU[int],
+		"FIFO": concurrent.NewFIFO[int],
+		"2Q":   concurrent.New2Q[int],
+	}
+
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			c := concurrent.NewBoundedCache[int, int](16, policy())
+
+			var wg sync.WaitGroup
+			for g := range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range 1000 {
+						key := (g*31 + i) % 64
+						c.Set(key, key)
+						c.Get(key / 2)
+						if i%10 == 0 {
+							c.Delete(key)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if c.Len() > 16 {
+				t.Errorf("want at most 16 items, got %d", c.Len())
+			}
+		})
+	}
+}
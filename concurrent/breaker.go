@@ -0,0 +1,330 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrOpen is returned by CircuitBreaker.Execute when the breaker is open, or
+// half-open with all of its trial calls already in flight.
+var ErrOpen = errors.New("concurrent: circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	StateClosed   BreakerState = iota // Calls go through and failures are counted
+	StateOpen                         // Calls fail fast with ErrOpen until the cooldown ends
+	StateHalfOpen                     // A few trial calls decide whether to close again
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerOption configures a CircuitBreaker.
+type BreakerOption func(*CircuitBreaker)
+
+// WithConsecutiveFailures opens the breaker after n failures in a row.
+// This is the default, with n = 5, unless WithFailureRatio is given.
+func WithConsecutiveFailures(n int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.maxConsecutive = max(n, 1)
+	}
+}
+
+// WithFailureRatio opens the breaker once at least ratio of the calls made in
+// the last window failed, provided there were at least minCalls of them.
+func WithFailureRatio(ratio float64, window time.Duration, minCalls int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.ratio = ratio
+		b.minCalls = max(minCalls, 1)
+		b.window = newRollingWindow(window)
+	}
+}
+
+// WithCooldown sets how long the breaker stays open before letting trial
+// calls through. The default is 10 seconds.
+func WithCooldown(d time.Duration) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.cooldown = d
+	}
+}
+
+// WithHalfOpenCalls sets how many trial calls may run while half-open.
+// All of them must succeed for the breaker to close. The default is 1.
+func WithHalfOpenCalls(n int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.halfOpenCalls = max(n, 1)
+	}
+}
+
+// WithBreakerClock makes the breaker read the time from clock instead of SystemClock.
+func WithBreakerClock(clock Clock) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.clock = clock
+	}
+}
+
+// CircuitBreaker stops calling a failing dependency for a while so that it
+// can recover. It starts closed and opens when failures reach a threshold.
+// Once the cooldown has passed it turns half-open and lets a few trial calls
+// through: if they all succeed it closes again, otherwise it reopens.
+//
+// A CircuitBreaker must be created with NewCircuitBreaker and is safe for concurrent use.
+type CircuitBreaker struct {
+	mu            sync.Mutex
+	clock         Clock
+	state         BreakerState
+	generation    uint64 // Incremented on every state change, to ignore stale outcomes
+	openedAt      time.Time
+	cooldown      time.Duration
+	halfOpenCalls int
+	inFlight      int // Trial calls running while half-open
+	succeeded     int // Trial calls that succeeded while half-open
+
+	maxConsecutive int // Zero if disabled
+	consecutive    int
+
+	ratio    float64
+	minCalls int
+	window   *rollingWindow // Nil if the failure ratio is disabled
+
+	onStateChange atomic.Pointer[func(from, to BreakerState)]
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		clock:         SystemClock,
+		cooldown:      10 * time.Second,
+		halfOpenCalls: 1,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.maxConsecutive == 0 && b.window == nil {
+		b.maxConsecutive = 5
+	}
+	return b
+}
+
+// OnStateChange registers fn to be called after every state change.
+// It replaces any previously registered callback.
+// fn is called without any lock held and may use the breaker.
+func (b *CircuitBreaker) OnStateChange(fn func(from, to BreakerState)) {
+	b.onStateChange.Store(&fn)
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	from, to := b.refresh(b.clock.Now())
+	state := b.state
+	b.mu.Unlock()
+
+	b.changed(from, to)
+	return state
+}
+
+// Execute calls fn if the breaker allows it and records the outcome.
+// It returns ErrOpen without calling fn if the breaker is open.
+// Errors caused by ctx being done are returned but not counted as failures.
+// If fn panics, the panic is counted as a failure and returned as a *PanicError.
+func (b *CircuitBreaker) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	generation, err := b.before()
+	if err != nil {
+		return err
+	}
+
+	_, err = runTask(ctx, job[struct{}]{task: wrapTask(fn)})
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		b.after(generation, nil, false)
+	} else {
+		b.after(generation, err, true)
+	}
+	return err
+}
+
+// before admits a call, returning the generation it belongs to.
+func (b *CircuitBreaker) before() (uint64, error) {
+	b.mu.Lock()
+	from, to := b.refresh(b.clock.Now())
+
+	var err error
+	switch b.state {
+	case StateOpen:
+		err = ErrOpen
+	case StateHalfOpen:
+		if b.inFlight+b.succeeded >= b.halfOpenCalls {
+			err = ErrOpen
+		} else {
+			b.inFlight++
+		}
+	}
+	generation := b.generation
+	b.mu.Unlock()
+
+	b.changed(from, to)
+	return generation, err
+}
+
+// after records the outcome of a call admitted in generation. If count is
+// false, the call only releases its half-open slot.
+func (b *CircuitBreaker) after(generation uint64, err error, count bool) {
+	b.mu.Lock()
+	now := b.clock.Now()
+	from, to := b.refresh(now)
+
+	if generation == b.generation {
+		switch b.state {
+		case StateClosed:
+			if count {
+				from, to = b.recordClosed(now, err)
+			}
+		case StateHalfOpen:
+			b.inFlight--
+			if count && err != nil {
+				from, to = b.setState(now, StateOpen)
+			} else if count {
+				b.succeeded++
+				if b.succeeded >= b.halfOpenCalls {
+					from, to = b.setState(now, StateClosed)
+				}
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	b.changed(from, to)
+}
+
+// recordClosed counts the outcome of a call made while closed and opens the
+// breaker if a threshold is reached. b.mu must be held.
+func (b *CircuitBreaker) recordClosed(now time.Time, err error) (from, to BreakerState) {
+	if b.window != nil {
+		b.window.add(now, err != nil)
+	}
+	if err == nil {
+		b.consecutive = 0
+		return b.state, b.state
+	}
+	b.consecutive++
+
+	if b.maxConsecutive > 0 && b.consecutive >= b.maxConsecutive {
+		return b.setState(now, StateOpen)
+	}
+	if b.window != nil {
+		calls, failures := b.window.counts(now)
+		if calls >= b.minCalls && float64(failures) >= b.ratio*float64(calls) {
+			return b.setState(now, StateOpen)
+		}
+	}
+	return b.state, b.state
+}
+
+// refresh moves an open breaker to half-open once the cooldown has passed.
+// b.mu must be held.
+func (b *CircuitBreaker) refresh(now time.Time) (from, to BreakerState) {
+	if b.state == StateOpen && now.Sub(b.openedAt) >= b.cooldown {
+		return b.setState(now, StateHalfOpen)
+	}
+	return b.state, b.state
+}
+
+// setState moves the breaker to state and resets the counters of the new state.
+// It returns the old and new state for changed. b.mu must be held.
+func (b *CircuitBreaker) setState(now time.Time, state BreakerState) (from, to BreakerState) {
+	from = b.state
+	b.state = state
+	b.generation++
+	b.consecutive, b.inFlight, b.succeeded = 0, 0, 0
+
+	switch state {
+	case StateOpen:
+		b.openedAt = now
+	case StateClosed:
+		if b.window != nil {
+			b.window.reset()
+		}
+	}
+	return from, state
+}
+
+// changed calls the state change callback if from and to differ.
+func (b *CircuitBreaker) changed(from, to BreakerState) {
+	if from == to {
+		return
+	}
+	if fn := b.onStateChange.Load(); fn != nil {
+		(*fn)(from, to)
+	}
+}
+
+// rollingWindowBuckets is the number of buckets a rollingWindow is split into.
+const rollingWindowBuckets = 10
+
+// rollingWindow counts calls and failures over a sliding period of time,
+// split into buckets that expire one at a time.
+type rollingWindow struct {
+	bucket  time.Duration
+	buckets [rollingWindowBuckets]windowBucket
+}
+
+type windowBucket struct {
+	epoch    int64 // Which bucket-sized slice of time the counts belong to
+	calls    int
+	failures int
+}
+
+func newRollingWindow(window time.Duration) *rollingWindow {
+	return &rollingWindow{bucket: max(window/rollingWindowBuckets, 1)}
+}
+
+func (w *rollingWindow) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(w.bucket)
+}
+
+func (w *rollingWindow) add(now time.Time, failed bool) {
+	epoch := w.epoch(now)
+	// Epochs before 1970 are negative; keep the index in range.
+	b := &w.buckets[(epoch%rollingWindowBuckets+rollingWindowBuckets)%rollingWindowBuckets]
+	if b.epoch != epoch {
+		*b = windowBucket{epoch: epoch}
+	}
+	b.calls++
+	if failed {
+		b.failures++
+	}
+}
+
+func (w *rollingWindow) counts(now time.Time) (calls, failures int) {
+	epoch := w.epoch(now)
+	for _, b := range w.buckets {
+		if b.epoch > epoch-rollingWindowBuckets && b.epoch <= epoch {
+			calls += b.calls
+			failures += b.failures
+		}
+	}
+	return calls, failures
+}
+
+func (w *rollingWindow) reset() {
+	w.buckets = [rollingWindowBuckets]windowBucket{}
+}
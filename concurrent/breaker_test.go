@@ -0,0 +1,231 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+var errDependency = errors.New("dependency failed")
+
+func fail(context.Context) error    { return errDependency }
+func succeed(context.Context) error { return nil }
+
+// recordStates registers a callback on b that records every state change.
+func recordStates(b *concurrent.CircuitBreaker) func() []concurrent.BreakerState {
+	var mu sync.Mutex
+	var states []concurrent.BreakerState
+	b.OnStateChange(func(_, to concurrent.BreakerState) {
+		mu.Lock()
+		states = append(states, to)
+		mu.Unlock()
+	})
+	return func() []concurrent.BreakerState {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(states)
+	}
+}
+
+func TestCircuitBreakerConsecutiveFailures(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrent.NewCircuitBreaker(
+		concurrent.WithConsecutiveFailures(3),
+		concurrent.WithCooldown(time.Minute),
+		concurrent.WithBreakerClock(clock),
+	)
+	states := recordStates(b)
+	ctx := context.Background()
+
+	// A success resets the count of consecutive failures.
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	b.Execute(ctx, succeed)
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	if b.State() != concurrent.StateClosed {
+		t.Fatalf("want closed, got %v", b.State())
+	}
+
+	if err := b.Execute(ctx, fail); err != errDependency {
+		t.Errorf("want %v, got %v", errDependency, err)
+	}
+	if b.State() != concurrent.StateOpen {
+		t.Fatalf("want open, got %v", b.State())
+	}
+
+	called := false
+	err := b.Execute(ctx, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if err != concurrent.ErrOpen || called {
+		t.Errorf("want %v without calling fn, got %v", concurrent.ErrOpen, err)
+	}
+
+	clock.Advance(time.Minute)
+	if b.State() != concurrent.StateHalfOpen {
+		t.Fatalf("want half-open, got %v", b.State())
+	}
+	if err := b.Execute(ctx, succeed); err != nil {
+		t.Errorf("want <nil>, got %v", err)
+	}
+	if b.State() != concurrent.StateClosed {
+		t.Errorf("want closed, got %v", b.State())
+	}
+
+	want := []concurrent.BreakerState{concurrent.StateOpen, concurrent.StateHalfOpen, concurrent.StateClosed}
+	if got := states(); !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrent.NewCircuitBreaker(
+		concurrent.WithConsecutiveFailures(1),
+		concurrent.WithCooldown(time.Second),
+		concurrent.WithBreakerClock(clock),
+	)
+	ctx := context.Background()
+
+	b.Execute(ctx, fail)
+	clock.Advance(time.Second)
+	b.Execute(ctx, fail)
+	if b.State() != concurrent.StateOpen {
+		t.Fatalf("want open, got %v", b.State())
+	}
+
+	// The cooldown starts over when the breaker reopens.
+	clock.Advance(999 * time.Millisecond)
+	if err := b.Execute(ctx, succeed); err != concurrent.ErrOpen {
+		t.Errorf("want %v, got %v", concurrent.ErrOpen, err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenCalls(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrent.NewCircuitBreaker(
+		concurrent.WithConsecutiveFailures(1),
+		concurrent.WithHalfOpenCalls(2),
+		concurrent.WithCooldown(time.Second),
+		concurrent.WithBreakerClock(clock),
+	)
+	ctx := context.Background()
+
+	b.Execute(ctx, fail)
+	clock.Advance(time.Second)
+
+	// Two trial calls may run at once; a third is rejected.
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Execute(ctx, func(context.Context) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+
+	if err := b.Execute(ctx, succeed); err != concurrent.ErrOpen {
+		t.Errorf("want %v, got %v", concurrent.ErrOpen, err)
+	}
+
+	close(release)
+	wg.Wait()
+	if b.State() != concurrent.StateClosed {
+		t.Errorf("want closed after both trials succeed, got %v", b.State())
+	}
+}
+
+func TestCircuitBreakerFailureRatio(t *testing.T) {
+	clock := newFakeClock()
+	b := concurrent.NewCircuitBreaker(
+		concurrent.WithFailureRatio(0.5, 10*time.Second, 4),
+		concurrent.WithBreakerClock(clock),
+	)
+	ctx := context.Background()
+
+	// Below the minimum number of calls, failures alone do not open it.
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	if b.State() != concurrent.StateClosed {
+		t.Fatalf("want closed, got %v", b.State())
+	}
+
+	// Old calls leave the window.
+	clock.Advance(11 * time.Second)
+	b.Execute(ctx, succeed)
+	b.Execute(ctx, succeed)
+	b.Execute(ctx, fail)
+	if b.State() != concurrent.StateClosed {
+		t.Fatalf("want closed with 1 of 3 calls failed, got %v", b.State())
+	}
+
+	b.Execute(ctx, fail)
+	if b.State() != concurrent.StateOpen {
+		t.Errorf("want open with 2 of 4 calls failed, got %v", b.State())
+	}
+}
+
+func TestCircuitBreakerFailureRatioBefore1970(t *testing.T) {
+	// Three seconds before the Unix epoch, so bucket epochs are negative.
+	clock := &fakeClock{now: time.Date(1969, 12, 31, 23, 59, 57, 0, time.UTC)}
+	b := concurrent.NewCircuitBreaker(
+		concurrent.WithFailureRatio(0.5, 10*time.Second, 2),
+		concurrent.WithBreakerClock(clock),
+	)
+	ctx := context.Background()
+
+	b.Execute(ctx, fail)
+	b.Execute(ctx, fail)
+	if b.State() != concurrent.StateOpen {
+		t.Errorf("want open, got %v", b.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresContextErrors(t *testing.T) {
+	b := concurrent.NewCircuitBreaker(concurrent.WithConsecutiveFailures(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := b.Execute(ctx, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+	if b.State() != concurrent.StateClosed {
+		t.Errorf("want closed, got %v", b.State())
+	}
+
+	if err := b.Execute(ctx, succeed); !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v without calling fn, got %v", context.Canceled, err)
+	}
+}
+
+func TestCircuitBreakerPanic(t *testing.T) {
+	b := concurrent.NewCircuitBreaker(concurrent.WithConsecutiveFailures(1))
+
+	err := b.Execute(context.Background(), func(context.Context) error { panic("oops") })
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("want PanicError, got %v", err)
+	}
+	if b.State() != concurrent.StateOpen {
+		t.Errorf("want open, got %v", b.State())
+	}
+}
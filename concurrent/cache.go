@@ -0,0 +1,269 @@
+package concurrent
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EvictReason tells an eviction callback why an entry left a cache.
+type EvictReason int
+
+const (
+	EvictExpired  EvictReason = iota // The entry's TTL elapsed
+	EvictDeleted                     // The entry was deleted explicitly
+	EvictCapacity                    // The entry was evicted to make room
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictCapacity:
+		return "capacity"
+	default:
+		return "unknown"
+	}
+}
+
+// CacheOption configures a Cache.
+type CacheOption func(*cacheOptions)
+
+type cacheOptions struct {
+	clock           Clock
+	cleanupInterval time.Duration
+}
+
+// WithClock makes the cache read the time from clock instead of SystemClock.
+func WithClock(clock Clock) CacheOption {
+	return func(o *cacheOptions) {
+		o.clock = clock
+	}
+}
+
+// WithCleanupInterval starts a janitor goroutine that removes expired
+// entries every interval. Call Stop to end it. Without it, expired entries
+// are only removed when they are read or by DeleteExpired.
+func WithCleanupInterval(interval time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.cleanupInterval = interval
+	}
+}
+
+// cacheEntry is a cached value and when it expires.
+// A zero expires means the entry never expires.
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func (e cacheEntry[V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// Cache is a concurrent map whose entries expire after a time-to-live.
+// It is built on Map and offers the same Get/Set/Delete API.
+//
+// Expired entries are never returned. They are removed lazily when read,
+// and periodically if the cache was created with WithCleanupInterval.
+type Cache[K comparable, V any] struct {
+	entries *Map[K, cacheEntry[V]]
+	ttl     time.Duration
+	clock   Clock
+	onEvict atomic.Pointer[func(K, V, EvictReason)]
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewCache creates a cache whose entries expire after defaultTTL.
+// If defaultTTL is zero or negative, entries do not expire by default.
+func NewCache[K comparable, V any](defaultTTL time.Duration, opts ...CacheOption) *Cache[K, V] {
+	o := cacheOptions{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Cache[K, V]{
+		entries: NewMap[K, cacheEntry[V]](),
+		ttl:     defaultTTL,
+		clock:   o.clock,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if o.cleanupInterval > 0 {
+		go c.janitor(o.cleanupInterval)
+	} else {
+		close(c.done)
+	}
+	return c
+}
+
+// OnEvict registers fn to be called after an entry expires or is deleted.
+// It replaces any previously registered callback.
+// fn is called without any lock held and may use the cache.
+func (c *Cache[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	c.onEvict.Store(&fn)
+}
+
+func (c *Cache[K, V]) evicted(key K, value V, reason EvictReason) {
+	if fn := c.onEvict.Load(); fn != nil {
+		(*fn)(key, value, reason)
+	}
+}
+
+// Get returns the value associated with the given key.
+// If the key is not found or has expired, Get returns the zero value for the value type and false.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	e, ok := c.entries.Get(key)
+	if !ok {
+		return value, false
+	}
+
+	now := c.clock.Now()
+	if !e.expired(now) {
+		return e.value, true
+	}
+
+	c.expire(key, now)
+	return value, false
+}
+
+// Set sets the given value to the given key, expiring after the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL sets the given value to the given key, expiring after ttl.
+// If ttl is zero or negative, the entry does not expire.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	e := cacheEntry[V]{value: value}
+	if ttl > 0 {
+		e.expires = c.clock.Now().Add(ttl)
+	}
+	c.entries.Set(key, e)
+}
+
+// Delete deletes the item with the given key from the cache.
+func (c *Cache[K, V]) Delete(key K) {
+	e, ok := c.entries.LoadAndDelete(key)
+	if !ok {
+		return
+	}
+
+	if e.expired(c.clock.Now()) {
+		c.evicted(key, e.value, EvictExpired)
+	} else {
+		c.evicted(key, e.value, EvictDeleted)
+	}
+}
+
+// expire removes key if it is still expired at now and reports the eviction.
+func (c *Cache[K, V]) expire(key K, now time.Time) {
+	var (
+		old     cacheEntry[V]
+		removed bool
+	)
+
+	// The entry may have been replaced since it was read.
+	c.entries.Compute(key, func(e cacheEntry[V], ok bool) (cacheEntry[V], bool) {
+		if ok && e.expired(now) {
+			old, removed = e, true
+			return e, false
+		}
+		return e, ok
+	})
+
+	if removed {
+		c.evicted(key, old.value, EvictExpired)
+	}
+}
+
+// DeleteExpired removes all expired entries from the cache.
+func (c *Cache[K, V]) DeleteExpired() {
+	now := c.clock.Now()
+
+	var expired []K
+	c.entries.Range(func(key K, e cacheEntry[V]) bool {
+		if e.expired(now) {
+			expired = append(expired, key)
+		}
+		return true
+	})
+
+	for _, key := range expired {
+		c.expire(key, now)
+	}
+}
+
+// Len returns the number of unexpired items in the cache.
+func (c *Cache[K, V]) Len() int {
+	length := 0
+	c.Range(func(K, V) bool {
+		length++
+		return true
+	})
+	return length
+}
+
+// Range calls f sequentially for each unexpired key and value in the cache.
+// If f returns false, range stops the iteration.
+// As with Map.Range, f must not write to the cache.
+func (c *Cache[K, V]) Range(f func(key K, value V) bool) {
+	now := c.clock.Now()
+	c.entries.Range(func(key K, e cacheEntry[V]) bool {
+		if e.expired(now) {
+			return true
+		}
+		return f(key, e.value)
+	})
+}
+
+// Keys returns all unexpired keys in the cache.
+func (c *Cache[K, V]) Keys() []K {
+	var keys []K
+	c.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns all unexpired values in the cache.
+func (c *Cache[K, V]) Values() []V {
+	var values []V
+	c.Range(func(_ K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Clear removes all items from the cache without calling the eviction callback.
+func (c *Cache[K, V]) Clear() {
+	c.entries.Clear()
+}
+
+// Stop ends the janitor goroutine, if any, and waits for it to exit.
+// The cache remains usable afterwards. It is safe to call Stop more than once.
+func (c *Cache[K, V]) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+// janitor removes expired entries every interval until Stop is called.
+func (c *Cache[K, V]) janitor(interval time.Duration) {
+	defer close(c.done)
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-c.clock.After(interval):
+			c.DeleteExpired()
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package concurrent_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+type eviction struct {
+	key    string
+	value  int
+	reason concurrent.EvictReason
+}
+
+// recordEvictions registers a callback on c that records every eviction.
+func recordEvictions(c *concurrent.Cache[string, int]) func() []eviction {
+	var mu sync.Mutex
+	var got []eviction
+	c.OnEvict(func(key string, value int, reason concurrent.EvictReason) {
+		mu.Lock()
+		got = append(got, eviction{key, value, reason})
+		mu.Unlock()
+	})
+	return func() []eviction {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]eviction(nil), got...)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	clock := newFakeClock()
+	c := concurrent.NewCache[string, int](time.Minute, concurrent.WithClock(clock))
+	evictions := recordEvictions(c)
+
+	c.Set("default", 1)
+	c.SetWithTTL("short", 2, time.Second)
+	c.SetWithTTL("forever", 3, 0)
+
+	if c.Len() != 3 {
+		t.Errorf("want 3 items, got %d", c.Len())
+	}
+
+	clock.Advance(time.Second)
+	if _, ok := c.Get("short"); ok {
+		t.Error("want short-lived entry to expire")
+	}
+	if v, ok := c.Get("default"); !ok || v != 1 {
+		t.Errorf("want 1 true, got %d %v", v, ok)
+	}
+
+	clock.Advance(time.Hour)
+	if _, ok := c.Get("default"); ok {
+		t.Error("want default entry to expire")
+	}
+	if v, ok := c.Get("forever"); !ok || v != 3 {
+		t.Errorf("want 3 true, got %d %v", v, ok)
+	}
+
+	want := []eviction{
+		{"short", 2, concurrent.EvictExpired},
+		{"default", 1, concurrent.EvictExpired},
+	}
+	got := evictions()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestCacheDelete(t *testing.T) {
+	c := concurrent.NewCache[string, int](0)
+	evictions := recordEvictions(c)
+
+	c.Set("one", 1)
+	c.Delete("one")
+	c.Delete("missing")
+
+	if _, ok := c.Get("one"); ok {
+		t.Error("want key to be not found, got found")
+	}
+
+	got := evictions()
+	if len(got) != 1 || got[0] != (eviction{"one", 1, concurrent.EvictDeleted}) {
+		t.Errorf("want one deletion, got %v", got)
+	}
+}
+
+func TestCacheRangeSkipsExpired(t *testing.T) {
+	clock := newFakeClock()
+	c := concurrent.NewCache[string, int](time.Second, concurrent.WithClock(clock))
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	clock.Advance(time.Minute)
+
+	if keys := c.Keys(); len(keys) != 1 || keys[0] != "b" {
+		t.Errorf("want [b], got %v", keys)
+	}
+	if values := c.Values(); len(values) != 1 || values[0] != 2 {
+		t.Errorf("want [2], got %v", values)
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("want 0 items, got %d", c.Len())
+	}
+}
+
+func TestCacheJanitor(t *testing.T) {
+	clock := newFakeClock()
+	c := concurrent.NewCache[string, int](time.Second,
+		concurrent.WithClock(clock), concurrent.WithCleanupInterval(time.Minute))
+	defer c.Stop()
+
+	evicted := make(chan string, 2)
+	c.OnEvict(func(key string, _ int, reason concurrent.EvictReason) {
+		if reason == concurrent.EvictExpired {
+			evicted <- key
+		}
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// wait for the janitor to be waiting on the clock
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Minute)
+
+	for range 2 {
+		select {
+		case <-evicted:
+		case <-time.After(5 * time.Second):
+			t.Fatal("want janitor to evict expired entries")
+		}
+	}
+
+	c.Stop()
+	c.Stop()
+}
@@ -0,0 +1,19 @@
+package concurrent
+
+import "time"
+
+// Clock tells the time and waits for it to pass. Types that expire or
+// throttle things accept a Clock so tests can control time deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
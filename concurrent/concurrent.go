@@ -2,83 +2,440 @@ package concurrent
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"time"
 )
 
 // Parallel runs the given tasks in parallel with a maximum number of workers.
 // It returns the first error encountered, or nil if all tasks completed successfully.
 // If stopOnError is true, it returns the first error encountered and stops processing.
+// If maxWorkers is not positive, one worker per task is started; it used to
+// mean that no task was run.
 //
 // The implementation uses a worker pool to limit the number of concurrent tasks.
 // It supports context cancellation and deadlines to stop processing early.
+// A task that panics does not crash the process; the panic is recovered
+// and reported as a *PanicError.
+//
+// Parallel is a shorthand for ParallelContext with tasks that ignore their context.
 func Parallel(ctx context.Context, tasks []func() error, maxWorkers int, stopOnError ...bool) error {
-	tasksCh := make(chan func() error, len(tasks))
-	resultsCh := make(chan error, len(tasks))
-	stopOnErr := false
-	if len(stopOnError) > 0 {
-		stopOnErr = stopOnError[0]
+	var opts []Option
+	if len(stopOnError) > 0 && stopOnError[0] {
+		opts = append(opts, StopOnError())
 	}
 
-	// Use a WaitGroup to wait for all goroutines to finish.
-	var wg sync.WaitGroup
-	wg.Add(maxWorkers)
+	ctxTasks := make([]func(context.Context) error, len(tasks))
+	for i, task := range tasks {
+		ctxTasks[i] = func(context.Context) error { return task() }
+	}
+	return ParallelContext(ctx, ctxTasks, maxWorkers, opts...)
+}
 
-	// Start worker goroutines.
-	for i := 0; i < maxWorkers; i++ {
-		go func() {
-			defer wg.Done()
-			worker(tasksCh, resultsCh)
-		}()
+// ParallelContext runs the given tasks in parallel with a maximum number of workers.
+// It returns the first error encountered, or nil if all tasks completed successfully.
+// With CollectErrors, it returns all errors joined instead.
+// If maxWorkers is not positive, one worker per task is started.
+//
+// Tasks receive a child context of ctx that is canceled when ctx is done, when
+// ParallelContext returns or, with StopOnError, after the first failure.
+// Once that context is canceled no more queued tasks are started.
+// ParallelContext returns as soon as the outcome is known; give Wait to also
+// wait for tasks that are still running so that no goroutines outlive the call.
+func ParallelContext(ctx context.Context, tasks []func(context.Context) error, maxWorkers int, opts ...Option) error {
+	return parallelContext(ctx, tasks, maxWorkers, newOptions(opts))
+}
+
+// ParallelWeighted is like ParallelContext, but limits the total weight of the
+// running tasks to capacity instead of their number. weights[i] is the weight
+// of tasks[i], such as its expected memory use. Tasks are started in order,
+// each as soon as enough capacity is free, so a heavy task is not overtaken
+// by lighter ones queued after it.
+//
+// It returns an error wrapping ErrWeightTooLarge, without running anything,
+// if a weight exceeds capacity. It panics if the slices differ in length
+// or a weight is negative.
+func ParallelWeighted(ctx context.Context, tasks []func(context.Context) error, weights []int64, capacity int64, opts ...Option) error {
+	if len(tasks) != len(weights) {
+		panic("concurrent: tasks and weights must have the same length")
+	}
+	for i, w := range weights {
+		if w < 0 {
+			panic("concurrent: negative task weight")
+		}
+		if w > capacity {
+			return fmt.Errorf("task %d: %w", i, ErrWeightTooLarge)
+		}
 	}
 
-	// Send tasks to workers.
-	for _, task := range tasks {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case tasksCh <- task:
+	o := newOptions(opts)
+	o.sem = NewSemaphore(capacity)
+	o.weights = weights
+	return parallelContext(ctx, tasks, maxAdmitted(weights, capacity), o)
+}
+
+// maxAdmitted returns the largest number of tasks that can hold capacity at
+// the same time, which is how many of the lightest ones fit. No more workers
+// than that are ever busy.
+func maxAdmitted(weights []int64, capacity int64) int {
+	var n int
+	var total int64
+	for _, w := range slices.Sorted(slices.Values(weights)) {
+		if total+w > capacity {
+			break
 		}
+		total += w
+		n++
 	}
-	close(tasksCh)
+	return n
+}
 
-	// Wait for all tasks to complete.
-	go func() {
-		wg.Wait()
-		close(resultsCh)
-	}()
+func parallelContext(ctx context.Context, tasks []func(context.Context) error, maxWorkers int, o *options) error {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Record the first failure as it happens. Tasks canceled because of it
+	// may report their own errors before it is read from the results.
+	var (
+		mu       sync.Mutex
+		firstErr error
+		errs     []error
+	)
+	onError := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		errs = append(errs, err)
+		mu.Unlock()
 
-	var taskErr error
+		if o.stopOnError {
+			cancel(ErrSkipped)
+		}
+	}
+
+	jobs := make([]func(context.Context) (struct{}, error), len(tasks))
+	for i, task := range tasks {
+		jobs[i] = wrapTask(task)
+	}
+	resultsCh := dispatch(runCtx, jobs, maxWorkers, o, onError)
+
+	var panicErr *PanicError
+	finish := func(err error) error {
+		cancel(ErrSkipped)
+		if o.wait {
+			for r := range resultsCh {
+				if panicErr == nil {
+					errors.As(r.Err, &panicErr)
+				}
+			}
+		}
+		if o.repanic && panicErr != nil {
+			panic(panicErr)
+		}
+		return err
+	}
 
-loop:
-	// Read results.
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err() // Context canceled or deadline exceeded.
-		case err, ok := <-resultsCh:
-			// Drain the results channel, but return the first error.
-			if !ok {
-				break loop // resultsCh has been closed.
+			return finish(ctx.Err()) // Context canceled or deadline exceeded.
+		case r, ok := <-resultsCh:
+			if panicErr == nil && ok {
+				errors.As(r.Err, &panicErr)
 			}
-			if stopOnErr && err != nil {
-				return err
+
+			// Tasks stop being dispatched once ctx is done, which may
+			// close resultsCh before ctx.Done is selected.
+			if !ok && ctx.Err() != nil {
+				return finish(ctx.Err())
 			}
 
-			// Save the first error.
-			if taskErr == nil && err != nil {
-				taskErr = err
+			// Stop once all tasks have finished, or at the first error.
+			if !ok || (o.stopOnError && r.Err != nil) {
+				mu.Lock()
+				err := firstErr
+				if o.collectErrors {
+					err = errors.Join(errs...)
+				}
+				mu.Unlock()
+
+				// A job skipped because ctx is done may be read before
+				// ctx.Done is selected; it has no error of its own recorded.
+				if err == nil && r.Err != nil {
+					err = ctx.Err()
+				}
+				return finish(err)
 			}
+		}
+	}
+}
+
+// ErrSkipped is reported for tasks that were never started because
+// processing stopped early.
+var ErrSkipped = errors.New("concurrent: task skipped")
+
+// PanicError is returned in place of a task's error when the task panics.
+type PanicError struct {
+	Value any    // Value passed to panic
+	Index int    // Position of the task in the submitted slice, or submission order in a WorkerPool
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("concurrent: task %d panicked: %v", e.Index, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Option configures how tasks are run by ParallelContext, ParallelResults
+// and the task groups.
+type Option func(*options)
+
+type options struct {
+	stopOnError   bool
+	repanic       bool
+	wait          bool
+	collectErrors bool
+	limiter       RateLimiter
+	retry         *RetryPolicy
+
+	// Set by ParallelWeighted: tasks[i] holds weights[i] units of sem while it runs.
+	sem     *Semaphore
+	weights []int64
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// StopOnError stops dispatching queued tasks after the first task fails
+// and cancels the context of the tasks that are still running.
+func StopOnError() Option {
+	return func(o *options) {
+		o.stopOnError = true
+	}
+}
+
+// Repanic re-raises the first task panic on the caller's goroutine instead of
+// reporting it as a *PanicError. The value passed to panic is the *PanicError,
+// so the original stack is kept.
+func Repanic() Option {
+	return func(o *options) {
+		o.repanic = true
+	}
+}
 
+// CollectErrors makes ParallelContext and the task groups return all task
+// errors joined with errors.Join, in the order they occurred, instead of
+// only the first. Task groups then no longer cancel their context when a
+// task fails. Combined with StopOnError, only the errors seen before
+// stopping are returned.
+func CollectErrors() Option {
+	return func(o *options) {
+		o.collectErrors = true
+	}
+}
+
+// RateLimit starts tasks no faster than limiter allows, on top of the limit
+// on concurrent workers. Tasks still waiting for a permit when processing
+// stops are not started.
+func RateLimit(limiter RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// Retries retries failing tasks according to policy. A task that still fails
+// reports the error of its last attempt, wrapped in a *RetryError if it was
+// retried, or joined with the context's error if retrying stopped because the
+// context is done. ParallelResults reports the number of attempts of every task.
+// StopOnError only takes effect once a task has given up.
+func Retries(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+// Wait makes ParallelContext wait for running tasks to finish before returning,
+// even when it stops early because of an error or ctx being done.
+// ParallelResults always waits.
+func Wait() Option {
+	return func(o *options) {
+		o.wait = true
+	}
+}
+
+// TaskResult is the outcome of a single task run by ParallelResults.
+type TaskResult[T any] struct {
+	Index    int           // Position of the task in the submitted slice
+	Value    T             // Value returned by the task
+	Err      error         // Error returned by the task, or why it did not run
+	Attempts int           // Number of times the task was run, zero if it did not run
+	Duration time.Duration // Time spent running the task, including retries
+}
+
+// ParallelResults runs the given tasks in parallel with a maximum number of workers
+// and returns the outcome of every task in submission order.
+// If maxWorkers is not positive, one worker per task is started.
+//
+// Tasks receive a context derived from ctx that is canceled when ctx is done or,
+// with StopOnError, after the first failure. Tasks that were never started report
+// ErrSkipped, or the context error if ctx itself was done. Tasks that panic report
+// a *PanicError, unless Repanic is given.
+// ParallelResults returns only after all started tasks have finished.
+func ParallelResults[T any](ctx context.Context, tasks []func(context.Context) (T, error), maxWorkers int, opts ...Option) []TaskResult[T] {
+	o := newOptions(opts)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Stop as soon as a task fails, not when its result is read,
+	// so that no worker picks up another task in the meantime.
+	onError := func(error) {}
+	if o.stopOnError {
+		onError = func(error) { cancel(ErrSkipped) }
+	}
+
+	var panicErr *PanicError
+	results := make([]TaskResult[T], len(tasks))
+	seen := make([]bool, len(tasks))
+	for r := range dispatch(ctx, tasks, maxWorkers, o, onError) {
+		results[r.Index] = r
+		seen[r.Index] = true
+		if panicErr == nil {
+			errors.As(r.Err, &panicErr)
+		}
+	}
+
+	if o.repanic && panicErr != nil {
+		panic(panicErr)
+	}
+
+	for i := range results {
+		if !seen[i] {
+			results[i] = TaskResult[T]{Index: i, Err: context.Cause(ctx)}
+		}
+	}
+	return results
+}
+
+// job is a task tagged with its position in the submitted slice.
+type job[T any] struct {
+	index  int
+	weight int64 // Units of options.sem held while the job runs
+	task   func(context.Context) (T, error)
+}
+
+// dispatch starts up to maxWorkers workers and feeds them tasks in submission
+// order until the tasks run out or ctx is done. If maxWorkers is not positive,
+// one worker per task is started. Tasks are fed no faster than o.limiter
+// allows, if set, and retried according to o.retry. The outcome of every
+// dispatched task is sent on the returned channel, which is buffered for all
+// tasks and closed once all workers have exited.
+func dispatch[T any](ctx context.Context, tasks []func(context.Context) (T, error), maxWorkers int, o *options, onError func(error)) <-chan TaskResult[T] {
+	if maxWorkers <= 0 || maxWorkers > len(tasks) {
+		maxWorkers = len(tasks)
+	}
+
+	jobsCh := make(chan job[T])
+	resultsCh := make(chan TaskResult[T], len(tasks))
+
+	// Use a WaitGroup to wait for all workers to finish.
+	var wg sync.WaitGroup
+	wg.Add(maxWorkers)
+	for i := 0; i < maxWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			worker(ctx, jobsCh, resultsCh, o, onError)
+		}()
+	}
+
+	// Send tasks to workers until they run out or ctx is done.
+	go func() {
+		defer close(jobsCh)
+		for i, task := range tasks {
+			j := job[T]{index: i, task: task}
+			if o.sem != nil {
+				j.weight = o.weights[i]
+				if o.sem.Acquire(ctx, j.weight) != nil {
+					return
+				}
+			}
+			if o.limiter != nil && o.limiter.Wait(ctx) != nil {
+				o.release(j.weight)
+				return
+			}
+			select {
+			case <-ctx.Done():
+				o.release(j.weight)
+				return
+			case jobsCh <- j:
+			}
 		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+	return resultsCh
+}
+
+// worker runs jobs, retrying them according to o.retry, and sends their outcome
+// to results, calling onError for every failed job. Jobs received after ctx is
+// done are reported without being run.
+func worker[T any](ctx context.Context, jobs <-chan job[T], results chan<- TaskResult[T], o *options, onError func(error)) {
+	for j := range jobs {
+		if err := context.Cause(ctx); err != nil {
+			o.release(j.weight)
+			results <- TaskResult[T]{Index: j.index, Err: err}
+			continue
+		}
+
+		start := time.Now()
+		value, attempts, err := retryTask(ctx, o.retry, j)
+		o.release(j.weight)
+		if err != nil {
+			onError(err)
+		}
+		results <- TaskResult[T]{Index: j.index, Value: value, Err: err, Attempts: attempts, Duration: time.Since(start)}
 	}
+}
 
-	// All tasks completed successfully.
-	return taskErr
+// release gives back the weight of a job, if tasks are weighted.
+func (o *options) release(weight int64) {
+	if o.sem != nil {
+		o.sem.Release(weight)
+	}
 }
 
-// worker runs tasks from tasks channel and sends the result to results channel.
-func worker(tasks <-chan func() error, results chan<- error) {
-	for task := range tasks {
-		results <- task()
+// wrapTask adapts a task that only returns an error to the job signature.
+func wrapTask(task func(context.Context) error) func(context.Context) (struct{}, error) {
+	return func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, task(ctx)
 	}
 }
+
+// runTask runs j, converting a panic into a *PanicError.
+func runTask[T any](ctx context.Context, j job[T]) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Index: j.index, Stack: debug.Stack()}
+		}
+	}()
+	return j.task(ctx)
+}
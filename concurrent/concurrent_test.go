@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -133,3 +134,236 @@ func TestParallelSum(t *testing.T) {
 	}
 
 }
+
+func TestParallelResults(t *testing.T) {
+	errOdd := errors.New("odd")
+	tasks := make([]func(context.Context) (int, error), 10)
+	for i := range tasks {
+		tasks[i] = func(ctx context.Context) (int, error) {
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			if i%2 != 0 {
+				return 0, errOdd
+			}
+			return i * i, nil
+		}
+	}
+
+	results := ParallelResults(context.Background(), tasks, 4)
+	if len(results) != len(tasks) {
+		t.Fatalf("want %d results, got %d", len(tasks), len(results))
+	}
+
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("want index %d, got %d", i, r.Index)
+		}
+		if i%2 != 0 {
+			if !errors.Is(r.Err, errOdd) {
+				t.Errorf("task %d: want %v, got %v", i, errOdd, r.Err)
+			}
+			continue
+		}
+		if r.Err != nil || r.Value != i*i {
+			t.Errorf("task %d: want %d <nil>, got %d %v", i, i*i, r.Value, r.Err)
+		}
+		if r.Duration <= 0 {
+			t.Errorf("task %d: want a positive duration, got %v", i, r.Duration)
+		}
+	}
+}
+
+func TestParallelResultsStopOnError(t *testing.T) {
+	errFail := errors.New("fail")
+	started := make(chan struct{})
+
+	tasks := []func(context.Context) (string, error){
+		func(ctx context.Context) (string, error) {
+			<-started
+			return "", errFail
+		},
+		func(ctx context.Context) (string, error) {
+			close(started)
+			<-ctx.Done()
+			return "canceled", ctx.Err()
+		},
+	}
+	for range 5 {
+		tasks = append(tasks, func(ctx context.Context) (string, error) {
+			return "ran", nil
+		})
+	}
+
+	results := ParallelResults(context.Background(), tasks, 2, StopOnError())
+
+	if !errors.Is(results[0].Err, errFail) {
+		t.Errorf("want %v, got %v", errFail, results[0].Err)
+	}
+	if results[1].Value != "canceled" || !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("want in-flight task to observe cancellation, got %q %v", results[1].Value, results[1].Err)
+	}
+	for _, r := range results[2:] {
+		if !errors.Is(r.Err, ErrSkipped) {
+			t.Errorf("task %d: want %v, got %q %v", r.Index, ErrSkipped, r.Value, r.Err)
+		}
+	}
+}
+
+func TestParallelResultsContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tasks := []func(context.Context) (int, error){
+		func(ctx context.Context) (int, error) { return 1, nil },
+	}
+
+	results := ParallelResults(ctx, tasks, 1)
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, results[0].Err)
+	}
+}
+
+func TestParallelRecoversPanic(t *testing.T) {
+	tasks := []func() error{
+		func() error { return nil },
+		func() error { panic("boom") },
+	}
+
+	err := Parallel(context.Background(), tasks, 2)
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("want a *PanicError, got %v", err)
+	}
+	if pe.Index != 1 || pe.Value != "boom" {
+		t.Errorf("want task 1 to panic with %q, got task %d with %v", "boom", pe.Index, pe.Value)
+	}
+	if len(pe.Stack) == 0 {
+		t.Error("want a stack trace, got none")
+	}
+}
+
+func TestParallelResultsPanic(t *testing.T) {
+	errBoom := errors.New("boom")
+	tasks := []func(context.Context) (int, error){
+		func(context.Context) (int, error) { return 1, nil },
+		func(context.Context) (int, error) { panic(errBoom) },
+	}
+
+	results := ParallelResults(context.Background(), tasks, 2)
+	var pe *PanicError
+	if !errors.As(results[1].Err, &pe) || pe.Index != 1 {
+		t.Errorf("want a *PanicError for task 1, got %v", results[1].Err)
+	}
+	if !errors.Is(results[1].Err, errBoom) {
+		t.Errorf("want the panic value to be unwrapped, got %v", results[1].Err)
+	}
+	if results[0].Value != 1 {
+		t.Errorf("want 1, got %d", results[0].Value)
+	}
+
+	defer func() {
+		r := recover()
+		if pe, ok := r.(*PanicError); !ok || pe.Index != 1 {
+			t.Errorf("want to re-panic with a *PanicError, got %v", r)
+		}
+	}()
+	ParallelResults(context.Background(), tasks, 2, Repanic())
+}
+
+func TestParallelContextStopsDispatching(t *testing.T) {
+	errFail := errors.New("fail")
+	var ran atomic.Int32
+
+	tasks := []func(context.Context) error{
+		func(ctx context.Context) error { return errFail },
+	}
+	for range 10 {
+		tasks = append(tasks, func(ctx context.Context) error {
+			ran.Add(1)
+			return nil
+		})
+	}
+
+	err := ParallelContext(context.Background(), tasks, 1, StopOnError(), Wait())
+	if !errors.Is(err, errFail) {
+		t.Errorf("want %v, got %v", errFail, err)
+	}
+	if n := ran.Load(); n != 0 {
+		t.Errorf("want no queued tasks to run, got %d", n)
+	}
+}
+
+func TestParallelContextCancelsTasks(t *testing.T) {
+	errFail := errors.New("fail")
+	started := make(chan struct{})
+	var canceled atomic.Bool
+
+	tasks := []func(context.Context) error{
+		func(ctx context.Context) error {
+			<-started
+			return errFail
+		},
+		func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			canceled.Store(true)
+			return ctx.Err()
+		},
+	}
+
+	err := ParallelContext(context.Background(), tasks, 2, StopOnError(), Wait())
+	if !errors.Is(err, errFail) {
+		t.Errorf("want %v, got %v", errFail, err)
+	}
+
+	// With Wait, the in-flight task must have finished before returning.
+	if !canceled.Load() {
+		t.Error("want in-flight task to be canceled and waited for")
+	}
+}
+
+func TestParallelContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var finished atomic.Bool
+	tasks := []func(context.Context) error{
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			finished.Store(true)
+			return ctx.Err()
+		},
+	}
+
+	err := ParallelContext(ctx, tasks, 1, Wait())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if !finished.Load() {
+		t.Error("want task to observe the deadline before returning")
+	}
+}
+
+func TestParallelContextCanceledStopOnError(t *testing.T) {
+	// Jobs skipped once ctx is canceled may be read before ctx.Done is
+	// selected; the cancellation must still be reported.
+	for range 2000 {
+		ctx, cancel := context.WithCancel(context.Background())
+		tasks := make([]func(context.Context) error, 256)
+		for i := range tasks {
+			tasks[i] = func(ctx context.Context) error {
+				if i == 128 {
+					cancel()
+				}
+				return nil
+			}
+		}
+
+		err := ParallelContext(ctx, tasks, 16, StopOnError(), Wait())
+		cancel()
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want %v, got %v", context.Canceled, err)
+		}
+	}
+}
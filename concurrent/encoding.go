@@ -0,0 +1,198 @@
+package concurrent
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"slices"
+)
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// sortKeys sorts keys in place if K has a natural order: integers, floats and
+// strings are sorted by value, and encoding.TextMarshaler implementations by
+// their text form. Other key types are left in map order.
+func sortKeys[K comparable](keys []K) {
+	t := reflect.TypeFor[K]()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		})
+	case reflect.Float32, reflect.Float64:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		})
+	case reflect.String:
+		slices.SortFunc(keys, func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		})
+	default:
+		if t.Kind() != reflect.Interface && t.Implements(textMarshalerType) {
+			text := make(map[K][]byte, len(keys))
+			for _, k := range keys {
+				text[k], _ = any(k).(encoding.TextMarshaler).MarshalText()
+			}
+			slices.SortFunc(keys, func(a, b K) int {
+				return bytes.Compare(text[a], text[b])
+			})
+		}
+	}
+}
+
+// MarshalJSON encodes a snapshot of the map as a JSON object.
+// K must be a string, an integer or implement encoding.TextMarshaler,
+// as for a plain Go map. Keys are sorted, so the output is deterministic.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return json.Marshal(m.m)
+}
+
+// UnmarshalJSON replaces the contents of the map with the decoded JSON object.
+// As is the convention for encoding/json, JSON null leaves the map unchanged.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	decoded := make(map[K]V)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if decoded != nil { // Unmarshal sets the map to nil for null
+		m.m = decoded
+	} else if m.m == nil {
+		m.m = make(map[K]V) // Keep a zero Map usable
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// gobMap is the wire form of a Map: keys sorted as by sortKeys and
+// values in the same order.
+type gobMap[K comparable, V any] struct {
+	Keys   []K
+	Values []V
+}
+
+// GobEncode encodes a snapshot of the map. Keys with a natural order
+// are sorted, so the output is deterministic.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	m.mu.RLock()
+	keys := make([]K, 0, len(m.m))
+	for k := range m.m {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+
+	values := make([]V, len(keys))
+	for i, k := range keys {
+		values[i] = m.m[k]
+	}
+	m.mu.RUnlock()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobMap[K, V]{Keys: keys, Values: values}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the contents of the map with the decoded data.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	var decoded gobMap[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+
+	entries := make(map[K]V, len(decoded.Keys))
+	for i, k := range decoded.Keys {
+		if i < len(decoded.Values) {
+			entries[k] = decoded.Values[i]
+		} else {
+			var zero V
+			entries[k] = zero
+		}
+	}
+
+	m.mu.Lock()
+	m.m = entries
+	m.mu.Unlock()
+	return nil
+}
+
+// sorted returns a snapshot of the elements of the set, sorted as by sortKeys.
+func (s *Set[K]) sorted() []K {
+	s.mu.RLock()
+	keys := make([]K, 0, len(s.m))
+	for k := range s.m {
+		keys = append(keys, k)
+	}
+	s.mu.RUnlock()
+
+	sortKeys(keys)
+	return keys
+}
+
+// replace replaces the elements of the set with keys.
+func (s *Set[K]) replace(keys []K) {
+	m := make(map[K]struct{}, len(keys))
+	for _, k := range keys {
+		m[k] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.m = m
+	s.mu.Unlock()
+}
+
+// MarshalJSON encodes a snapshot of the set as a JSON array. Elements with
+// a natural order are sorted, so the output is deterministic.
+func (s *Set[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.sorted())
+}
+
+// UnmarshalJSON replaces the elements of the set with the decoded JSON array.
+// As is the convention for encoding/json, JSON null leaves the set unchanged.
+func (s *Set[K]) UnmarshalJSON(data []byte) error {
+	var keys []K
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	if keys == nil { // null; an empty array decodes to an empty slice
+		s.mu.Lock()
+		if s.m == nil {
+			s.m = make(map[K]struct{}) // Keep a zero Set usable
+		}
+		s.mu.Unlock()
+		return nil
+	}
+	s.replace(keys)
+	return nil
+}
+
+// GobEncode encodes a snapshot of the set. Elements with a natural order
+// are sorted, so the output is deterministic.
+func (s *Set[K]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s.sorted()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the elements of the set with the decoded data.
+func (s *Set[K]) GobDecode(data []byte) error {
+	var keys []K
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
@@ -0,0 +1,149 @@
+package concurrent_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestMapJSON(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	m.Set("b", 2)
+	m.Set("a", 1)
+	m.Set("c", 3)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if want := `{"a":1,"b":2,"c":3}`; string(data) != want {
+		t.Errorf("want %s, got %s", want, data)
+	}
+
+	// decoding works on a zero Map embedded in a struct
+	var got struct {
+		Counts concurrent.Map[string, int]
+	}
+	if err := json.Unmarshal([]byte(`{"Counts":`+string(data)+`}`), &got); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got.Counts.Len() != 3 {
+		t.Errorf("want 3 items, got %d", got.Counts.Len())
+	}
+	if v, _ := got.Counts.Get("c"); v != 3 {
+		t.Errorf("want 3, got %d", v)
+	}
+}
+
+func TestJSONNull(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	m.Set("a", 1)
+	s := concurrent.NewSetFrom(1, 2)
+
+	// null is a no-op, as for other encoding/json unmarshalers
+	if err := json.Unmarshal([]byte(`null`), m); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("want map unchanged, got %d %v", v, ok)
+	}
+	if err := json.Unmarshal([]byte(`null`), s); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if s.Len() != 2 {
+		t.Errorf("want set unchanged, got %d elements", s.Len())
+	}
+
+	// a zero Map decoded from null must still be usable
+	var got struct {
+		Counts concurrent.Map[string, int]
+	}
+	if err := json.Unmarshal([]byte(`{"Counts":null}`), &got); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	got.Counts.Set("b", 2)
+	if v, _ := got.Counts.Get("b"); v != 2 {
+		t.Errorf("want 2, got %d", v)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	s := concurrent.NewSetFrom(10, 2, 33, 1)
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if want := `[1,2,10,33]`; string(data) != want {
+		t.Errorf("want %s, got %s", want, data)
+	}
+
+	// decoding replaces the previous contents
+	got := concurrent.NewSetFrom(99)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("want %v, got %v", s.Values(), got.Values())
+	}
+
+	// text marshalers are sorted by their text form
+	addrs := concurrent.NewSetFrom(netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.1"))
+	data, err = json.Marshal(addrs)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if want := `["10.0.0.1","10.0.0.2"]`; string(data) != want {
+		t.Errorf("want %s, got %s", want, data)
+	}
+}
+
+func TestMapGob(t *testing.T) {
+	m := concurrent.NewMap[int, string]()
+	for i := range 20 {
+		m.Set(i, string(rune('a'+i)))
+	}
+
+	var first, second bytes.Buffer
+	if err := gob.NewEncoder(&first).Encode(m); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if err := gob.NewEncoder(&second).Encode(m); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("want deterministic gob output")
+	}
+
+	got := concurrent.NewMap[int, string]()
+	if err := gob.NewDecoder(&first).Decode(got); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if got.Len() != 20 {
+		t.Errorf("want 20 items, got %d", got.Len())
+	}
+	if v, _ := got.Get(3); v != "d" {
+		t.Errorf("want d, got %s", v)
+	}
+}
+
+func TestSetGob(t *testing.T) {
+	s := concurrent.NewSetFrom("x", "y", "z")
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+
+	got := concurrent.NewSet[string]()
+	if err := gob.NewDecoder(&buf).Decode(got); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("want %v, got %v", s.Values(), got.Values())
+	}
+}
@@ -0,0 +1,187 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoFutures is returned by the Future of AnyOf and Race when they are given no futures.
+var ErrNoFutures = errors.New("concurrent: no futures")
+
+// Future is the eventual result of a function started with Async.
+// All methods are safe for concurrent use, and the result may be awaited
+// any number of times.
+type Future[T any] struct {
+	ctx    context.Context // Context Async was called with
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once value and err are set
+	value  T
+	err    error
+}
+
+// Async starts fn in a new goroutine and returns a Future for its result.
+// fn receives a child context of ctx that is canceled when ctx is done,
+// when Cancel is called or once fn returns.
+// If fn panics, the Future fails with a *PanicError.
+func Async[T any](ctx context.Context, fn func(context.Context) (T, error)) *Future[T] {
+	runCtx, cancel := context.WithCancel(ctx)
+	f := &Future[T]{ctx: ctx, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer cancel()
+		f.value, f.err = runTask(runCtx, job[T]{task: fn})
+		close(f.done)
+	}()
+	return f
+}
+
+// Done returns a channel that is closed once the result is ready.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Cancel cancels the context of the running function. The Future still
+// completes with whatever the function returns.
+func (f *Future[T]) Cancel() {
+	f.cancel()
+}
+
+// Await waits for the result and returns it.
+// If ctx is done first, Await returns ctx.Err(); the function keeps running.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// AwaitTimeout is like Await but gives up after timeout,
+// returning context.DeadlineExceeded.
+func (f *Future[T]) AwaitTimeout(timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return f.Await(ctx)
+}
+
+// Then returns a Future that calls fn with the value of f once it is ready.
+// If f fails, fn is not called and the returned Future fails with the same error.
+// fn runs with a context derived from the one f was started with.
+func Then[T, U any](f *Future[T], fn func(context.Context, T) (U, error)) *Future[U] {
+	return Async(f.ctx, func(ctx context.Context) (U, error) {
+		value, err := f.Await(ctx)
+		if err != nil {
+			var zero U
+			return zero, err
+		}
+		return fn(ctx, value)
+	})
+}
+
+// ThenAsync is like Then for a fn that itself starts asynchronous work.
+// The returned Future completes with the result of the Future returned by fn.
+func ThenAsync[T, U any](f *Future[T], fn func(context.Context, T) *Future[U]) *Future[U] {
+	return Then(f, func(ctx context.Context, value T) (U, error) {
+		return fn(ctx, value).Await(ctx)
+	})
+}
+
+// AllOf returns a Future for the values of all the given futures, in order.
+// It fails with the first error to occur, without waiting for the rest.
+// The given futures are not canceled.
+func AllOf[T any](ctx context.Context, futures ...*Future[T]) *Future[[]T] {
+	return Async(ctx, func(ctx context.Context) ([]T, error) {
+		values := make([]T, len(futures))
+		received := 0
+		for r := range awaitAll(ctx, futures) {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+			values[r.Index] = r.Value
+			received++
+		}
+		if received < len(futures) {
+			return nil, ctx.Err()
+		}
+		return values, nil
+	})
+}
+
+// AnyOf returns a Future for the value of the first of the given futures
+// to succeed. If all of them fail, it fails with their errors joined in order.
+// The other futures are not canceled.
+func AnyOf[T any](ctx context.Context, futures ...*Future[T]) *Future[T] {
+	return Async(ctx, func(ctx context.Context) (value T, err error) {
+		if len(futures) == 0 {
+			return value, ErrNoFutures
+		}
+
+		errs := make([]error, len(futures))
+		received := 0
+		for r := range awaitAll(ctx, futures) {
+			if r.Err == nil {
+				return r.Value, nil
+			}
+			errs[r.Index] = r.Err
+			received++
+		}
+		if received < len(futures) {
+			return value, ctx.Err()
+		}
+		return value, errors.Join(errs...)
+	})
+}
+
+// Race returns a Future for the result of the first of the given futures
+// to complete, whether it succeeded or failed.
+// The other futures are not canceled.
+func Race[T any](ctx context.Context, futures ...*Future[T]) *Future[T] {
+	return Async(ctx, func(ctx context.Context) (value T, err error) {
+		if len(futures) == 0 {
+			return value, ErrNoFutures
+		}
+
+		if r, ok := <-awaitAll(ctx, futures); ok {
+			return r.Value, r.Err
+		}
+		return value, ctx.Err()
+	})
+}
+
+// awaitAll awaits every future in its own goroutine and sends the results
+// in completion order. The channel is closed once all futures are done, or
+// early if ctx is done. The caller may stop reading at any time, provided
+// ctx is canceled afterwards so that the goroutines exit.
+func awaitAll[T any](ctx context.Context, futures []*Future[T]) <-chan TaskResult[T] {
+	results := make(chan TaskResult[T], len(futures))
+	for i, f := range futures {
+		go func() {
+			select {
+			case <-f.done:
+				results <- TaskResult[T]{Index: i, Value: f.value, Err: f.err}
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	out := make(chan TaskResult[T])
+	go func() {
+		defer close(out)
+		for range futures {
+			select {
+			case r := <-results:
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,182 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+// after returns a function that returns value and err after d.
+func after[T any](d time.Duration, value T, err error) func(context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		select {
+		case <-time.After(d):
+			return value, err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+func TestFutureAwait(t *testing.T) {
+	ctx := context.Background()
+	f := concurrent.Async(ctx, after(10*time.Millisecond, 42, nil))
+
+	for range 2 {
+		v, err := f.Await(ctx)
+		if v != 42 || err != nil {
+			t.Errorf("want 42 <nil>, got %d %v", v, err)
+		}
+	}
+
+	select {
+	case <-f.Done():
+	default:
+		t.Error("want Done to be closed")
+	}
+}
+
+func TestFutureAwaitTimeout(t *testing.T) {
+	f := concurrent.Async(context.Background(), after(time.Second, 1, nil))
+	defer f.Cancel()
+
+	if _, err := f.AwaitTimeout(10 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestFutureCancel(t *testing.T) {
+	f := concurrent.Async(context.Background(), after(time.Minute, 1, nil))
+	f.Cancel()
+
+	if _, err := f.AwaitTimeout(5 * time.Second); !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestFuturePanic(t *testing.T) {
+	f := concurrent.Async(context.Background(), func(context.Context) (int, error) {
+		panic("oops")
+	})
+
+	_, err := f.Await(context.Background())
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "oops" {
+		t.Errorf("want PanicError with value oops, got %v", err)
+	}
+}
+
+func TestFutureThen(t *testing.T) {
+	ctx := context.Background()
+	f := concurrent.Async(ctx, after(time.Millisecond, 21, nil))
+
+	doubled := concurrent.Then(f, func(_ context.Context, v int) (int, error) {
+		return v * 2, nil
+	})
+	text := concurrent.ThenAsync(doubled, func(ctx context.Context, v int) *concurrent.Future[string] {
+		return concurrent.Async(ctx, after(time.Millisecond, strconv.Itoa(v), nil))
+	})
+
+	if v, err := text.Await(ctx); v != "42" || err != nil {
+		t.Errorf("want 42 <nil>, got %q %v", v, err)
+	}
+
+	boom := errors.New("boom")
+	failed := concurrent.Async(ctx, after(time.Millisecond, 0, boom))
+	called := false
+	next := concurrent.Then(failed, func(_ context.Context, v int) (int, error) {
+		called = true
+		return v, nil
+	})
+	if _, err := next.Await(ctx); err != boom || called {
+		t.Errorf("want %v without calling fn, got %v %v", boom, err, called)
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	ctx := context.Background()
+	all := concurrent.AllOf(ctx,
+		concurrent.Async(ctx, after(30*time.Millisecond, 1, nil)),
+		concurrent.Async(ctx, after(10*time.Millisecond, 2, nil)),
+		concurrent.Async(ctx, after(20*time.Millisecond, 3, nil)),
+	)
+
+	values, err := all.Await(ctx)
+	if err != nil || len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Errorf("want [1 2 3] <nil>, got %v %v", values, err)
+	}
+
+	boom := errors.New("boom")
+	slow := concurrent.Async(ctx, after(time.Minute, 1, nil))
+	defer slow.Cancel()
+	failed := concurrent.AllOf(ctx, slow, concurrent.Async(ctx, after(time.Millisecond, 0, boom)))
+	if _, err := failed.AwaitTimeout(5 * time.Second); err != boom {
+		t.Errorf("want %v, got %v", boom, err)
+	}
+
+	empty, err := concurrent.AllOf[int](ctx).Await(ctx)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("want [] <nil>, got %v %v", empty, err)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	ctx := context.Background()
+	first, second := errors.New("first"), errors.New("second")
+
+	any := concurrent.AnyOf(ctx,
+		concurrent.Async(ctx, after(time.Millisecond, 0, first)),
+		concurrent.Async(ctx, after(20*time.Millisecond, 2, nil)),
+	)
+	if v, err := any.Await(ctx); v != 2 || err != nil {
+		t.Errorf("want 2 <nil>, got %d %v", v, err)
+	}
+
+	none := concurrent.AnyOf(ctx,
+		concurrent.Async(ctx, after(10*time.Millisecond, 0, first)),
+		concurrent.Async(ctx, after(time.Millisecond, 0, second)),
+	)
+	_, err := none.Await(ctx)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("want both errors, got %v", err)
+	}
+
+	if _, err := concurrent.AnyOf[int](ctx).Await(ctx); err != concurrent.ErrNoFutures {
+		t.Errorf("want %v, got %v", concurrent.ErrNoFutures, err)
+	}
+}
+
+func TestRace(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	race := concurrent.Race(ctx,
+		concurrent.Async(ctx, after(50*time.Millisecond, 1, nil)),
+		concurrent.Async(ctx, after(time.Millisecond, 0, boom)),
+	)
+	if _, err := race.Await(ctx); err != boom {
+		t.Errorf("want %v, got %v", boom, err)
+	}
+
+	if _, err := concurrent.Race[int](ctx).Await(ctx); err != concurrent.ErrNoFutures {
+		t.Errorf("want %v, got %v", concurrent.ErrNoFutures, err)
+	}
+}
+
+func TestCombinatorContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := concurrent.Async(context.Background(), after(time.Minute, 1, nil))
+	defer slow.Cancel()
+
+	all := concurrent.AllOf(ctx, slow)
+	cancel()
+
+	if _, err := all.AwaitTimeout(5 * time.Second); !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,210 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ResultGroup runs tasks submitted one at a time, like Parallel without the
+// need to know every task up front, and collects the values they return.
+// It is the typed counterpart of TaskGroup.
+//
+// Tasks receive a context derived from the one the group was created with,
+// which Context also returns. Like the context of errgroup.WithContext, it is
+// canceled after the first task fails, unless CollectErrors is given, and once
+// Wait returns. Tasks submitted after a task failed or after the parent context
+// is done are not run; if no task failed, Wait reports the cause instead.
+// The group can be reused after Wait, but tasks submitted then see a canceled
+// context, and the next Wait returns the values of all tasks so far.
+//
+// A ResultGroup must be created with NewResultGroup. Go, TryGo and Wait are
+// safe for concurrent use; SetLimit is not.
+type ResultGroup[T any] struct {
+	parent  context.Context
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	stopped atomic.Bool // Set once a failure stops new tasks from running
+	o       *options
+	wg      sync.WaitGroup
+	sem     chan struct{} // Holds a token per running task if there is a limit
+
+	mu       sync.Mutex
+	values   []T
+	firstErr error
+	errs     []error
+	panicErr *PanicError
+}
+
+// NewResultGroup creates a group whose tasks run with a context derived from ctx.
+// It accepts the CollectErrors and Repanic options.
+func NewResultGroup[T any](ctx context.Context, opts ...Option) *ResultGroup[T] {
+	g := &ResultGroup[T]{parent: ctx, o: newOptions(opts)}
+	g.ctx, g.cancel = context.WithCancelCause(ctx)
+	return g
+}
+
+// Context returns the context passed to the group's tasks.
+func (g *ResultGroup[T]) Context() context.Context {
+	return g.ctx
+}
+
+// SetLimit limits the number of running tasks to n. Go blocks and TryGo fails
+// while the limit is reached. A negative n removes the limit.
+// SetLimit must not be called while tasks are running.
+func (g *ResultGroup[T]) SetLimit(n int) {
+	if g.sem != nil && len(g.sem) != 0 {
+		panic("concurrent: SetLimit called while tasks are running")
+	}
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs task in a new goroutine, waiting first until the limit allows it.
+func (g *ResultGroup[T]) Go(task func(context.Context) (T, error)) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(task)
+}
+
+// TryGo runs task in a new goroutine only if the limit allows it right away.
+// It reports whether the task was started.
+func (g *ResultGroup[T]) TryGo(task func(context.Context) (T, error)) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(task)
+	return true
+}
+
+// start records task in submission order and runs it.
+func (g *ResultGroup[T]) start(task func(context.Context) (T, error)) {
+	g.mu.Lock()
+	j := job[T]{index: len(g.values), task: task}
+	g.values = append(g.values, *new(T))
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		// A skipped task reports why, unless an error that caused it was recorded.
+		if cause := g.skipCause(); cause != nil {
+			g.mu.Lock()
+			if g.firstErr == nil {
+				g.firstErr = cause
+				g.errs = append(g.errs, cause)
+			}
+			g.mu.Unlock()
+			return
+		}
+
+		value, err := runTask(g.ctx, j)
+
+		g.mu.Lock()
+		g.values[j.index] = value
+		if err != nil {
+			if g.firstErr == nil {
+				g.firstErr = err
+			}
+			g.errs = append(g.errs, err)
+			if g.panicErr == nil {
+				errors.As(err, &g.panicErr)
+			}
+		}
+		g.mu.Unlock()
+
+		if err != nil && (!g.o.collectErrors || g.o.stopOnError) {
+			g.stopped.Store(true)
+			g.cancel(ErrSkipped)
+		}
+	}()
+}
+
+// skipCause returns why new tasks are not run, or nil if they are. The
+// context canceled by Wait alone does not stop tasks, so the group stays usable.
+func (g *ResultGroup[T]) skipCause() error {
+	if g.stopped.Load() {
+		return ErrSkipped
+	}
+	return context.Cause(g.parent)
+}
+
+// Wait waits for all started tasks to finish, then returns their values in
+// submission order together with the first error, or all errors joined with
+// CollectErrors. Tasks that failed or were not run leave the zero value.
+// With Repanic, Wait re-raises the first task panic instead.
+func (g *ResultGroup[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	g.cancel(ErrSkipped)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.o.repanic && g.panicErr != nil {
+		panic(g.panicErr)
+	}
+
+	err := g.firstErr
+	if g.o.collectErrors {
+		err = errors.Join(g.errs...)
+	}
+	return slices.Clone(g.values), err
+}
+
+// TaskGroup runs tasks submitted one at a time and reports their errors,
+// in the style of golang.org/x/sync/errgroup. See ResultGroup for the details;
+// a TaskGroup is a ResultGroup for tasks that return no value.
+type TaskGroup struct {
+	g *ResultGroup[struct{}]
+}
+
+// NewTaskGroup creates a group whose tasks run with a context derived from ctx.
+// It accepts the CollectErrors and Repanic options.
+func NewTaskGroup(ctx context.Context, opts ...Option) *TaskGroup {
+	return &TaskGroup{g: NewResultGroup[struct{}](ctx, opts...)}
+}
+
+// Context returns the context passed to the group's tasks.
+func (g *TaskGroup) Context() context.Context {
+	return g.g.Context()
+}
+
+// SetLimit limits the number of running tasks to n. Go blocks and TryGo fails
+// while the limit is reached. A negative n removes the limit.
+// SetLimit must not be called while tasks are running.
+func (g *TaskGroup) SetLimit(n int) {
+	g.g.SetLimit(n)
+}
+
+// Go runs task in a new goroutine, waiting first until the limit allows it.
+func (g *TaskGroup) Go(task func(context.Context) error) {
+	g.g.Go(wrapTask(task))
+}
+
+// TryGo runs task in a new goroutine only if the limit allows it right away.
+// It reports whether the task was started.
+func (g *TaskGroup) TryGo(task func(context.Context) error) bool {
+	return g.g.TryGo(wrapTask(task))
+}
+
+// Wait waits for all started tasks to finish and returns the first error,
+// or all errors joined with CollectErrors.
+// With Repanic, Wait re-raises the first task panic instead.
+func (g *TaskGroup) Wait() error {
+	_, err := g.g.Wait()
+	return err
+}
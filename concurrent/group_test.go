@@ -0,0 +1,232 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestTaskGroup(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+
+	var count atomic.Int32
+	for range 10 {
+		g.Go(func(context.Context) error {
+			count.Add(1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("want <nil>, got %v", err)
+	}
+	if count.Load() != 10 {
+		t.Errorf("want 10 tasks run, got %d", count.Load())
+	}
+}
+
+func TestTaskGroupFirstErrorCancels(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+	boom := errors.New("boom")
+
+	g.Go(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("want context to be canceled")
+		}
+	})
+	g.Go(func(context.Context) error { return boom })
+
+	if err := g.Wait(); err != boom {
+		t.Errorf("want %v, got %v", boom, err)
+	}
+
+	ran := false
+	g.Go(func(context.Context) error {
+		ran = true
+		return nil
+	})
+	g.Wait()
+	if ran {
+		t.Error("want tasks submitted after Wait not to run")
+	}
+}
+
+func TestTaskGroupReuseAfterWait(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+	g.Go(func(context.Context) error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if g.Context().Err() == nil {
+		t.Error("want the context to be canceled once Wait returns")
+	}
+
+	var ran atomic.Bool
+	g.Go(func(context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if !ran.Load() {
+		t.Error("want tasks submitted after a successful Wait to run")
+	}
+}
+
+func TestTaskGroupContext(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+	ctx := g.Context()
+
+	g.Go(func(context.Context) error { return errors.New("boom") })
+	<-ctx.Done() // canceled by the failure, before Wait
+	g.Wait()
+}
+
+func TestTaskGroupCollectErrors(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background(), concurrent.CollectErrors())
+	first, second := errors.New("first"), errors.New("second")
+
+	g.SetLimit(1)
+	g.Go(func(context.Context) error { return first })
+	g.Go(func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			return errors.New("want context not to be canceled")
+		}
+		return second
+	})
+	g.Go(func(context.Context) error { return nil })
+
+	err := g.Wait()
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("want both errors, got %v", err)
+	}
+}
+
+func TestTaskGroupLimit(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+	g.SetLimit(2)
+
+	var running, peak atomic.Int32
+	for range 10 {
+		g.Go(func(context.Context) error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	g.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("want at most 2 tasks running, got %d", peak.Load())
+	}
+}
+
+func TestTaskGroupTryGo(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+	g.SetLimit(1)
+
+	release := make(chan struct{})
+	if !g.TryGo(func(context.Context) error {
+		<-release
+		return nil
+	}) {
+		t.Fatal("want first TryGo to start the task")
+	}
+	if g.TryGo(func(context.Context) error { return nil }) {
+		t.Error("want TryGo to fail while the limit is reached")
+	}
+
+	close(release)
+	g.Wait()
+	if !g.TryGo(func(context.Context) error { return nil }) {
+		t.Error("want TryGo to succeed once the task finished")
+	}
+	g.Wait()
+}
+
+func TestTaskGroupPanic(t *testing.T) {
+	g := concurrent.NewTaskGroup(context.Background())
+	g.Go(func(context.Context) error { panic("oops") })
+
+	var panicErr *concurrent.PanicError
+	if err := g.Wait(); !errors.As(err, &panicErr) || panicErr.Value != "oops" {
+		t.Errorf("want PanicError with value oops, got %v", err)
+	}
+
+	g = concurrent.NewTaskGroup(context.Background(), concurrent.Repanic())
+	g.Go(func(context.Context) error { panic("oops") })
+
+	defer func() {
+		if _, ok := recover().(*concurrent.PanicError); !ok {
+			t.Error("want Wait to re-panic with a *PanicError")
+		}
+	}()
+	g.Wait()
+}
+
+func TestResultGroup(t *testing.T) {
+	g := concurrent.NewResultGroup[int](context.Background())
+	g.SetLimit(3)
+
+	for i := range 5 {
+		g.Go(func(context.Context) (int, error) {
+			time.Sleep(time.Duration(5-i) * time.Millisecond)
+			return i * i, nil
+		})
+	}
+
+	values, err := g.Wait()
+	if err != nil || !slices.Equal(values, []int{0, 1, 4, 9, 16}) {
+		t.Errorf("want [0 1 4 9 16] <nil>, got %v %v", values, err)
+	}
+}
+
+func TestResultGroupParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g := concurrent.NewResultGroup[int](ctx)
+	var ran atomic.Bool
+	g.Go(func(context.Context) (int, error) {
+		ran.Store(true)
+		return 1, nil
+	})
+
+	values, err := g.Wait()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+	if ran.Load() || !slices.Equal(values, []int{0}) {
+		t.Errorf("want task not to run, got %v", values)
+	}
+}
+
+func TestParallelContextCollectErrors(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	tasks := []func(context.Context) error{
+		func(context.Context) error { return first },
+		func(context.Context) error { return nil },
+		func(context.Context) error { return second },
+	}
+
+	err := concurrent.ParallelContext(context.Background(), tasks, 1, concurrent.CollectErrors())
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("want both errors, got %v", err)
+	}
+}
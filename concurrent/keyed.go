@@ -0,0 +1,73 @@
+package concurrent
+
+import "sync"
+
+// keyedLock is the lock of a single key and the number of goroutines
+// holding or waiting for it.
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// KeyedMutex provides a mutual exclusion lock per key, so that goroutines
+// working on different keys do not block each other.
+//
+// Locks are created on first use and removed once no goroutine holds or waits
+// for them, so memory use is bounded by the number of keys in use at a time.
+// A KeyedMutex must be created with NewKeyedMutex and is safe for concurrent use.
+type KeyedMutex[K comparable] struct {
+	locks *Map[K, *keyedLock]
+}
+
+// NewKeyedMutex creates a keyed mutex.
+func NewKeyedMutex[K comparable]() *KeyedMutex[K] {
+	return &KeyedMutex[K]{locks: NewMap[K, *keyedLock]()}
+}
+
+// ref returns the lock of key, creating it if needed, and counts a reference to it.
+func (m *KeyedMutex[K]) ref(key K) *keyedLock {
+	return m.locks.Upsert(key, func(l *keyedLock, ok bool) *keyedLock {
+		if !ok {
+			l = &keyedLock{}
+		}
+		l.refs++
+		return l
+	})
+}
+
+// unref drops a reference to the lock of key, removing it if it was the last.
+func (m *KeyedMutex[K]) unref(key K) {
+	m.locks.Compute(key, func(l *keyedLock, ok bool) (*keyedLock, bool) {
+		l.refs--
+		return l, l.refs > 0
+	})
+}
+
+// Lock locks key, blocking until it is available.
+func (m *KeyedMutex[K]) Lock(key K) {
+	m.ref(key).mu.Lock()
+}
+
+// TryLock tries to lock key without blocking and reports whether it succeeded.
+func (m *KeyedMutex[K]) TryLock(key K) bool {
+	if m.ref(key).mu.TryLock() {
+		return true
+	}
+	m.unref(key)
+	return false
+}
+
+// Unlock unlocks key. It panics if key is not locked.
+func (m *KeyedMutex[K]) Unlock(key K) {
+	l, ok := m.locks.Get(key)
+	if !ok {
+		panic("concurrent: unlock of unlocked key")
+	}
+	l.mu.Unlock()
+	m.unref(key)
+}
+
+// Len returns the number of keys that are locked or waited for.
+func (m *KeyedMutex[K]) Len() int {
+	return m.locks.Len()
+}
@@ -0,0 +1,77 @@
+package concurrent_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	m := concurrent.NewKeyedMutex[string]()
+
+	counts := map[string]int{}
+	var mu sync.Mutex // Guards the map itself; the keyed lock guards each count
+	var active [2]atomic.Int32
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := []string{"a", "b"}[i%2]
+
+			m.Lock(key)
+			defer m.Unlock(key)
+
+			if active[i%2].Add(1) != 1 {
+				t.Errorf("want one holder of key %s", key)
+			}
+			mu.Lock()
+			counts[key]++
+			mu.Unlock()
+			time.Sleep(100 * time.Microsecond)
+			active[i%2].Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if counts["a"] != 50 || counts["b"] != 50 {
+		t.Errorf("want 50 per key, got %v", counts)
+	}
+	if m.Len() != 0 {
+		t.Errorf("want all locks to be removed, got %d", m.Len())
+	}
+}
+
+func TestKeyedMutexTryLock(t *testing.T) {
+	m := concurrent.NewKeyedMutex[int]()
+
+	if !m.TryLock(1) {
+		t.Fatal("want TryLock to succeed")
+	}
+	if m.TryLock(1) {
+		t.Error("want TryLock of a locked key to fail")
+	}
+	if !m.TryLock(2) {
+		t.Error("want TryLock of another key to succeed")
+	}
+	if m.Len() != 2 {
+		t.Errorf("want 2 locks, got %d", m.Len())
+	}
+
+	m.Unlock(1)
+	m.Unlock(2)
+	if m.Len() != 0 {
+		t.Errorf("want 0 locks, got %d", m.Len())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("want Unlock of an unlocked key to panic")
+		}
+	}()
+	m.Unlock(3)
+}
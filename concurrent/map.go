@@ -1,12 +1,18 @@
 package concurrent
 
-import "sync"
+import (
+	"iter"
+	"maps"
+	"sync"
+)
 
 // Map is a concurrent map, safe for read and write operations.
 // Guarded by a read-write mutex.
 type Map[K comparable, V any] struct {
 	mu sync.RWMutex // Read-write mutex
 	m  map[K]V      // underlying map
+
+	group Group[K, V] // Coalesces GetOrCompute calls for missing keys
 }
 
 // NewMap creates a new concurrent map.
@@ -49,6 +55,7 @@ func (m *Map[K, V]) Len() int {
 
 // Range calls f sequentially for each key and value present in the map.
 // If f returns false, range stops the iteration.
+// The read lock is held while f runs, so f must not write to the map; see All.
 func (m *Map[K, V]) Range(f func(key K, value V) bool) {
 	m.mu.RLock()
 	for k, v := range m.m {
@@ -59,6 +66,67 @@ func (m *Map[K, V]) Range(f func(key K, value V) bool) {
 	m.mu.RUnlock()
 }
 
+// All returns an iterator over a snapshot of the key-value pairs in the map.
+//
+// The snapshot is copied under the read lock, and no lock is held while yielding,
+// so the loop body may freely read and write the map. Changes made during the
+// iteration are not reflected in it. Use AllLocked to avoid the copy.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mu.RLock()
+		snapshot := maps.Clone(m.m)
+		m.mu.RUnlock()
+
+		for k, v := range snapshot {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// AllLocked returns an iterator over the key-value pairs in the map that holds
+// the read lock for the whole iteration, like Range.
+//
+// The loop body must not write to the map, or it will deadlock. Long loop bodies
+// also block writers for their whole duration. Prefer All unless the copy matters.
+func (m *Map[K, V]) AllLocked() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		for k, v := range m.m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSeq returns an iterator over a snapshot of the keys in the map.
+// No lock is held while yielding; see All.
+func (m *Map[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, k := range m.Keys() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns an iterator over a snapshot of the values in the map.
+// No lock is held while yielding; see All.
+func (m *Map[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.Values() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 // Keys returns all keys in the map.
 func (m *Map[K, V]) Keys() []K {
 	m.mu.RLock()
@@ -87,3 +155,129 @@ func (m *Map[K, V]) Clear() {
 	clear(m.m)
 	m.mu.Unlock()
 }
+
+// GetOrSet returns the existing value for the key if present.
+// Otherwise, it sets the given value and returns it.
+// The loaded result is true if the value was already present.
+func (m *Map[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if actual, loaded = m.m[key]; loaded {
+		return actual, true
+	}
+	m.m[key] = value
+	return value, false
+}
+
+// GetOrCompute returns the existing value for the key if present.
+// Otherwise, it calls fn and stores the value it returns, unless fn fails.
+// Concurrent callers that miss the same key share a single call to fn
+// and all receive its result, so fn runs once per missing key.
+// fn runs without any lock held and may use the map.
+func (m *Map[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	if value, ok := m.Get(key); ok {
+		return value, nil
+	}
+
+	value, err, _ := m.group.Do(key, func() (V, error) {
+		// A call that finished after the miss above may have filled the key.
+		if value, ok := m.Get(key); ok {
+			return value, nil
+		}
+
+		value, err := fn()
+		if err != nil {
+			return value, err
+		}
+		m.Set(key, value)
+		return value, nil
+	})
+	return value, err
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+// The loaded result reports whether the key was present.
+func (m *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	m.mu.Lock()
+	value, loaded = m.m[key]
+	delete(m.m, key)
+	m.mu.Unlock()
+	return
+}
+
+// Compute calls fn with the current value of the key, if any, and stores the
+// value it returns. If fn returns keep as false, the key is deleted instead.
+// fn runs under the map's write lock and must not call methods on m.
+// Compute returns the new value and whether the key is now present.
+func (m *Map[K, V]) Compute(key K, fn func(old V, ok bool) (value V, keep bool)) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, ok := m.m[key]
+	value, keep := fn(old, ok)
+	if !keep {
+		delete(m.m, key)
+		var zero V
+		return zero, false
+	}
+	m.m[key] = value
+	return value, true
+}
+
+// Update replaces the value of an existing key with the result of fn.
+// It does nothing if the key is not present.
+// fn runs under the map's write lock and must not call methods on m.
+// Update returns the new value and whether the key was present.
+func (m *Map[K, V]) Update(key K, fn func(old V) V) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, ok := m.m[key]
+	if !ok {
+		return old, false
+	}
+	value := fn(old)
+	m.m[key] = value
+	return value, true
+}
+
+// Upsert stores the result of fn for the key, whether or not it is present.
+// fn receives the current value and whether there was one.
+// fn runs under the map's write lock and must not call methods on m.
+func (m *Map[K, V]) Upsert(key K, fn func(old V, ok bool) V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, ok := m.m[key]
+	value := fn(old, ok)
+	m.m[key] = value
+	return value
+}
+
+// CompareAndSwap swaps the old and new values for key
+// if the value stored in the map is equal to old.
+// It is a function rather than a method because V must be comparable.
+func CompareAndSwap[K, V comparable](m *Map[K, V], key K, old, new V) (swapped bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cur, ok := m.m[key]; !ok || cur != old {
+		return false
+	}
+	m.m[key] = new
+	return true
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old.
+// It is a function rather than a method because V must be comparable.
+func CompareAndDelete[K, V comparable](m *Map[K, V], key K, old V) (deleted bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cur, ok := m.m[key]; !ok || cur != old {
+		return false
+	}
+	delete(m.m, key)
+	return true
+}
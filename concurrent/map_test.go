@@ -2,6 +2,7 @@ package concurrent_test
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"testing"
 
@@ -122,3 +123,154 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Errorf("want 10 items, got %d", m.Len())
 	}
 }
+
+func TestMapGetOrSet(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+
+	v, loaded := m.GetOrSet("one", 1)
+	if loaded || v != 1 {
+		t.Errorf("want 1 false, got %d %v", v, loaded)
+	}
+
+	v, loaded = m.GetOrSet("one", 2)
+	if !loaded || v != 1 {
+		t.Errorf("want 1 true, got %d %v", v, loaded)
+	}
+}
+
+func TestMapLoadAndDelete(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	m.Set("one", 1)
+
+	v, loaded := m.LoadAndDelete("one")
+	if !loaded || v != 1 {
+		t.Errorf("want 1 true, got %d %v", v, loaded)
+	}
+
+	if _, loaded := m.LoadAndDelete("one"); loaded {
+		t.Error("want key to be not found, got found")
+	}
+}
+
+func TestMapCompareAndSwap(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	m.Set("one", 1)
+
+	if concurrent.CompareAndSwap(m, "one", 2, 3) {
+		t.Error("want swap to fail for a stale old value")
+	}
+	if !concurrent.CompareAndSwap(m, "one", 1, 3) {
+		t.Error("want swap to succeed")
+	}
+	if v, _ := m.Get("one"); v != 3 {
+		t.Errorf("want 3, got %d", v)
+	}
+	if concurrent.CompareAndSwap(m, "two", 0, 1) {
+		t.Error("want swap to fail for a missing key")
+	}
+
+	if concurrent.CompareAndDelete(m, "one", 1) {
+		t.Error("want delete to fail for a stale old value")
+	}
+	if !concurrent.CompareAndDelete(m, "one", 3) || m.Len() != 0 {
+		t.Error("want delete to succeed")
+	}
+}
+
+func TestMapCompute(t *testing.T) {
+	m := concurrent.NewMap[string, []int]()
+	appendOne := func(old []int, ok bool) ([]int, bool) {
+		return append(old, 1), true
+	}
+
+	m.Compute("a", appendOne)
+	v, ok := m.Compute("a", appendOne)
+	if !ok || len(v) != 2 {
+		t.Errorf("want [1 1] true, got %v %v", v, ok)
+	}
+
+	v, ok = m.Compute("a", func(old []int, ok bool) ([]int, bool) { return nil, false })
+	if ok || v != nil || m.Len() != 0 {
+		t.Errorf("want key to be deleted, got %v %v", v, ok)
+	}
+}
+
+func TestMapUpdateUpsert(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	inc := func(old int) int { return old + 1 }
+
+	if _, ok := m.Update("hits", inc); ok || m.Len() != 0 {
+		t.Error("want Update to skip a missing key")
+	}
+
+	if v := m.Upsert("hits", func(old int, ok bool) int { return old + 1 }); v != 1 {
+		t.Errorf("want 1, got %d", v)
+	}
+	if v, ok := m.Update("hits", inc); !ok || v != 2 {
+		t.Errorf("want 2 true, got %d %v", v, ok)
+	}
+}
+
+func TestMapComputeConcurrent(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Upsert("counter", func(old int, ok bool) int { return old + 1 })
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := m.Get("counter"); v != 100 {
+		t.Errorf("want 100, got %d", v)
+	}
+}
+
+func TestMapAll(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	m.Set("one", 1)
+	m.Set("two", 2)
+
+	// the loop body may write to the map without deadlocking
+	sum := 0
+	for k, v := range m.All() {
+		sum += v
+		m.Set(k+"!", v*10)
+		m.Delete(k)
+	}
+	if sum != 3 {
+		t.Errorf("want 3, got %d", sum)
+	}
+	if m.Len() != 2 {
+		t.Errorf("want 2 items, got %d", m.Len())
+	}
+
+	sum = 0
+	for _, v := range m.AllLocked() {
+		sum += v
+	}
+	if sum != 30 {
+		t.Errorf("want 30, got %d", sum)
+	}
+
+	var keys []string
+	for k := range m.KeysSeq() {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if !slices.Equal([]string{"one!", "two!"}, keys) {
+		t.Errorf("want [one! two!], got %v", keys)
+	}
+
+	count := 0
+	for range m.ValuesSeq() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("want iteration to stop after 1 value, got %d", count)
+	}
+}
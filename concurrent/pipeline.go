@@ -0,0 +1,278 @@
+package concurrent
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+)
+
+// Pipeline stages connect goroutines with channels. Every stage stops and
+// closes its output channels once its input is drained or ctx is done, so
+// a consumer that stops reading must cancel ctx to release the stages
+// upstream of it. No stage leaks a goroutine once ctx is canceled.
+
+// send sends v on out unless ctx is done first. It reports whether v was sent.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Generate returns a channel that receives the values of seq.
+func Generate[T any](ctx context.Context, seq iter.Seq[T]) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range seq {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// MapChan applies f to the values received from in using the given number
+// of workers, and sends the results in completion order. If workers is less
+// than one, a single worker is used. Use OrderedMapChan to keep input order.
+func MapChan[T, U any](ctx context.Context, in <-chan T, f func(T) U, workers int) <-chan U {
+	out := make(chan U)
+
+	var wg sync.WaitGroup
+	wg.Add(max(workers, 1))
+	for range max(workers, 1) {
+		go func() {
+			defer wg.Done()
+			for v := range orDone(ctx, in) {
+				if !send(ctx, out, f(v)) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// OrderedMapChan is like MapChan but sends the results in the order the
+// values were received, holding back results that finish early.
+// At most workers calls to f run at a time, and at most workers results
+// are held back.
+func OrderedMapChan[T, U any](ctx context.Context, in <-chan T, f func(T) U, workers int) <-chan U {
+	workers = max(workers, 1)
+
+	// Each value gets a channel for its result. The channels are queued in
+	// input order, and the queue's capacity bounds the results held back.
+	pending := make(chan chan U, workers)
+	tokens := make(chan struct{}, workers)
+	go func() {
+		defer close(pending)
+		for v := range orDone(ctx, in) {
+			if !send(ctx, tokens, struct{}{}) {
+				return
+			}
+			result := make(chan U, 1)
+			if !send(ctx, pending, result) {
+				return
+			}
+			go func() {
+				result <- f(v)
+				<-tokens
+			}()
+		}
+	}()
+
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for result := range pending {
+			select {
+			case u := <-result:
+				if !send(ctx, out, u) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FilterChan sends the values received from in for which f returns true.
+func FilterChan[T any](ctx context.Context, in <-chan T, f func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range orDone(ctx, in) {
+			if f(v) && !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanOut distributes the values received from in across n channels, each
+// value going to whichever channel is read first. If n is less than one,
+// a single channel is returned.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]<-chan T, max(n, 1))
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for v := range orDone(ctx, in) {
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// FanIn merges the values received from all the given channels into one,
+// in no particular order. The returned channel is closed once all inputs are.
+func FanIn[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func() {
+			defer wg.Done()
+			for v := range orDone(ctx, in) {
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Merge is an alias for FanIn.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	return FanIn(ctx, ins...)
+}
+
+// Tee sends every value received from in to each of n channels. A value is
+// only passed on once every channel has received it, so the slowest reader
+// sets the pace. If n is less than one, a single channel is returned.
+func Tee[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	chans := make([]chan T, max(n, 1))
+	outs := make([]<-chan T, len(chans))
+	for i := range chans {
+		chans[i] = make(chan T)
+		outs[i] = chans[i]
+	}
+
+	go func() {
+		defer func() {
+			for _, ch := range chans {
+				close(ch)
+			}
+		}()
+
+		for v := range orDone(ctx, in) {
+			for _, ch := range chans {
+				if !send(ctx, ch, v) {
+					return
+				}
+			}
+		}
+	}()
+	return outs
+}
+
+// Batch groups the values received from in into slices of up to size values.
+// A batch is sent once it is full, or once timeout has passed since its first
+// value arrived if timeout is positive. The last batch may be smaller, and a
+// partial batch is dropped if ctx is done.
+// If size is less than one, batches hold a single value.
+func Batch[T any](ctx context.Context, in <-chan T, size int, timeout time.Duration) <-chan []T {
+	size = max(size, 1)
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+
+		var (
+			batch []T
+			timer *time.Timer
+			fire  <-chan time.Time
+		)
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, fire = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && timeout > 0 {
+					timer = time.NewTimer(timeout)
+					fire = timer.C
+				}
+				if len(batch) >= size && !flush() {
+					return
+				}
+			case <-fire:
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// orDone returns an iterator over the values received from in that stops
+// when in is closed or ctx is done.
+func orDone[T any](ctx context.Context, in <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package concurrent_test
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn"
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+// drain collects the values received from ch until it is closed.
+func drain[T any](t *testing.T, ch <-chan T) []T {
+	t.Helper()
+	var values []T
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values
+			}
+			values = append(values, v)
+		case <-timeout:
+			t.Fatal("want channel to be closed")
+		}
+	}
+}
+
+// checkNoLeak fails the test if the number of goroutines does not return to
+// what it was when checkNoLeak was called.
+func checkNoLeak(t *testing.T) func() {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	return func() {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				t.Fatalf("want %d goroutines, got %d", before, runtime.NumGoroutine())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx := context.Background()
+
+	nums := concurrent.Generate(ctx, fn.FromSlice([]int{1, 2, 3, 4, 5, 6}))
+	even := concurrent.FilterChan(ctx, nums, func(n int) bool { return n%2 == 0 })
+	squares := concurrent.MapChan(ctx, even, func(n int) int { return n * n }, 3)
+
+	got := drain(t, squares)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{4, 16, 36}) {
+		t.Errorf("want [4 16 36], got %v", got)
+	}
+}
+
+func TestOrderedMapChan(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx := context.Background()
+
+	in := concurrent.Generate(ctx, fn.FromSlice([]int{5, 4, 3, 2, 1, 0}))
+	out := concurrent.OrderedMapChan(ctx, in, func(n int) int {
+		// Later values finish first.
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10
+	}, 4)
+
+	if got := drain(t, out); !slices.Equal(got, []int{50, 40, 30, 20, 10, 0}) {
+		t.Errorf("want [50 40 30 20 10 0], got %v", got)
+	}
+}
+
+func TestFanOutFanIn(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx := context.Background()
+
+	in := concurrent.Generate(ctx, fn.FromSlice([]int{1, 2, 3, 4, 5, 6, 7, 8}))
+	outs := concurrent.FanOut(ctx, in, 3)
+	if len(outs) != 3 {
+		t.Fatalf("want 3 channels, got %d", len(outs))
+	}
+
+	got := drain(t, concurrent.Merge(ctx, outs...))
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("want every value once, got %v", got)
+	}
+}
+
+func TestTee(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx := context.Background()
+
+	in := concurrent.Generate(ctx, fn.FromSlice([]string{"a", "b", "c"}))
+	outs := concurrent.Tee(ctx, in, 2)
+
+	results := make(chan []string, 2)
+	for _, out := range outs {
+		go func() { results <- drain(t, out) }()
+	}
+	for range 2 {
+		if got := <-results; !slices.Equal(got, []string{"a", "b", "c"}) {
+			t.Errorf("want [a b c], got %v", got)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx := context.Background()
+
+	in := concurrent.Generate(ctx, fn.FromSlice([]int{1, 2, 3, 4, 5}))
+	got := drain(t, concurrent.Batch(ctx, in, 2, 0))
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestBatchTimeout(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx := context.Background()
+
+	in := make(chan int)
+	batches := concurrent.Batch(ctx, in, 10, 10*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	select {
+	case b := <-batches:
+		if !slices.Equal(b, []int{1, 2}) {
+			t.Errorf("want [1 2], got %v", b)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want partial batch to be sent after the timeout")
+	}
+
+	close(in)
+	if rest := drain(t, batches); len(rest) != 0 {
+		t.Errorf("want no more batches, got %v", rest)
+	}
+}
+
+func TestPipelineCancelDoesNotLeak(t *testing.T) {
+	defer checkNoLeak(t)()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// An endless source whose consumer stops after one value.
+	naturals := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	in := concurrent.Generate(ctx, naturals)
+	mapped := concurrent.MapChan(ctx, in, func(n int) int { return n }, 4)
+	ordered := concurrent.OrderedMapChan(ctx, mapped, func(n int) int { return n }, 4)
+	filtered := concurrent.FilterChan(ctx, ordered, func(int) bool { return true })
+	tees := concurrent.Tee(ctx, filtered, 2)
+	outs := concurrent.FanOut(ctx, tees[0], 2)
+	merged := concurrent.FanIn(ctx, outs...)
+	batches := concurrent.Batch(ctx, merged, 3, time.Millisecond)
+
+	<-batches
+	cancel()
+	drain(t, batches)
+	drain(t, tees[1])
+}
@@ -0,0 +1,238 @@
+package concurrent
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// EvictionPolicy decides which key a BoundedCache evicts when it is full.
+//
+// A BoundedCache calls the policy with its write lock held, so implementations
+// need not be safe for concurrent use. A policy must not be shared between caches.
+type EvictionPolicy[K comparable] interface {
+	// Add records that key was inserted into the cache.
+	Add(key K)
+
+	// Access records that key was read or overwritten.
+	Access(key K)
+
+	// Remove records that key was deleted from the cache.
+	Remove(key K)
+
+	// Evict records that key was evicted from the cache to make room.
+	Evict(key K)
+
+	// Victim returns the key to evict next, or false if the policy tracks no keys.
+	// It does not remove the key; the cache calls Evict once it is evicted.
+	Victim() (K, bool)
+}
+
+// listPolicy keeps keys in a list ordered from most to least recently
+// added, and moves accessed keys to the front if touch is set.
+type listPolicy[K comparable] struct {
+	order *list.List
+	elems map[K]*list.Element
+	touch bool
+}
+
+func newListPolicy[K comparable](touch bool) *listPolicy[K] {
+	return &listPolicy[K]{order: list.New(), elems: make(map[K]*list.Element), touch: touch}
+}
+
+func (p *listPolicy[K]) Add(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.elems[key] = p.order.PushFront(key)
+}
+
+func (p *listPolicy[K]) Access(key K) {
+	if e, ok := p.elems[key]; ok && p.touch {
+		p.order.MoveToFront(e)
+	}
+}
+
+func (p *listPolicy[K]) Remove(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *listPolicy[K]) Evict(key K) {
+	p.Remove(key)
+}
+
+func (p *listPolicy[K]) Victim() (key K, ok bool) {
+	if e := p.order.Back(); e != nil {
+		return e.Value.(K), true
+	}
+	return key, false
+}
+
+// NewLRU returns a policy that evicts the least recently used key.
+func NewLRU[K comparable]() EvictionPolicy[K] {
+	return newListPolicy[K](true)
+}
+
+// NewFIFO returns a policy that evicts the oldest key, regardless of how often
+// or how recently it was used.
+func NewFIFO[K comparable]() EvictionPolicy[K] {
+	return newListPolicy[K](false)
+}
+
+// lfuItem is a key in the heap of an lfuPolicy.
+type lfuItem[K comparable] struct {
+	key   K
+	freq  uint64
+	tick  uint64 // When the key was last used, to break ties by recency
+	index int
+}
+
+// lfuHeap is a min-heap of keys ordered by frequency, then by last use.
+type lfuHeap[K comparable] []*lfuItem[K]
+
+func (h lfuHeap[K]) Len() int { return len(h) }
+
+func (h lfuHeap[K]) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].tick < h[j].tick
+}
+
+func (h lfuHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap[K]) Push(x any) {
+	item := x.(*lfuItem[K])
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap[K]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+type lfuPolicy[K comparable] struct {
+	heap  lfuHeap[K]
+	items map[K]*lfuItem[K]
+	tick  uint64
+}
+
+// NewLFU returns a policy that evicts the least frequently used key.
+// Among keys used equally often, the least recently used one is evicted.
+func NewLFU[K comparable]() EvictionPolicy[K] {
+	return &lfuPolicy[K]{items: make(map[K]*lfuItem[K])}
+}
+
+func (p *lfuPolicy[K]) Add(key K) {
+	if _, ok := p.items[key]; ok {
+		p.Access(key)
+		return
+	}
+	p.tick++
+	item := &lfuItem[K]{key: key, freq: 1, tick: p.tick}
+	p.items[key] = item
+	heap.Push(&p.heap, item)
+}
+
+func (p *lfuPolicy[K]) Access(key K) {
+	if item, ok := p.items[key]; ok {
+		p.tick++
+		item.freq++
+		item.tick = p.tick
+		heap.Fix(&p.heap, item.index)
+	}
+}
+
+func (p *lfuPolicy[K]) Remove(key K) {
+	if item, ok := p.items[key]; ok {
+		heap.Remove(&p.heap, item.index)
+		delete(p.items, key)
+	}
+}
+
+func (p *lfuPolicy[K]) Evict(key K) {
+	p.Remove(key)
+}
+
+func (p *lfuPolicy[K]) Victim() (key K, ok bool) {
+	if len(p.heap) == 0 {
+		return key, false
+	}
+	return p.heap[0].key, true
+}
+
+// twoQueue implements the simplified 2Q algorithm. New keys enter a FIFO
+// queue (in). Keys evicted from it are remembered in a ghost queue (out)
+// without their values, and are only promoted to the LRU queue (hot) if they
+// are added again while still remembered. A single scan over many keys
+// therefore only churns the in queue and leaves the hot keys alone.
+type twoQueue[K comparable] struct {
+	in, out, hot *listPolicy[K]
+}
+
+// New2Q returns a scan-resistant policy based on the 2Q algorithm.
+// About a quarter of the cache holds keys seen once; keys seen again
+// after leaving that part are kept in an LRU queue.
+func New2Q[K comparable]() EvictionPolicy[K] {
+	return &twoQueue[K]{
+		in:  newListPolicy[K](false),
+		out: newListPolicy[K](false),
+		hot: newListPolicy[K](true),
+	}
+}
+
+func (p *twoQueue[K]) Add(key K) {
+	if _, ok := p.out.elems[key]; ok {
+		p.out.Remove(key)
+		p.hot.Add(key)
+		return
+	}
+	p.in.Add(key)
+}
+
+func (p *twoQueue[K]) Access(key K) {
+	// Repeated hits on a key in the in queue are treated as correlated
+	// references and do not promote it.
+	p.hot.Access(key)
+}
+
+func (p *twoQueue[K]) Remove(key K) {
+	p.in.Remove(key)
+	p.hot.Remove(key)
+}
+
+func (p *twoQueue[K]) Evict(key K) {
+	if _, ok := p.in.elems[key]; ok {
+		p.in.Remove(key)
+
+		// Remember the key, keeping at most as many ghosts as there are
+		// tracked keys. Deleted keys are not remembered: only keys pushed
+		// out for lack of room have proven they deserve a second chance.
+		p.out.Add(key)
+		for p.out.order.Len() > max(1, p.in.order.Len()+p.hot.order.Len()) {
+			ghost, _ := p.out.Victim()
+			p.out.Remove(ghost)
+		}
+		return
+	}
+	p.hot.Remove(key)
+}
+
+func (p *twoQueue[K]) Victim() (key K, ok bool) {
+	size := p.in.order.Len() + p.hot.order.Len()
+	if p.in.order.Len() > size/4 || p.hot.order.Len() == 0 {
+		return p.in.Victim()
+	}
+	return p.hot.Victim()
+}
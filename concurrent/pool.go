@@ -0,0 +1,353 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrPoolClosed is returned when submitting to a pool that has been shut down,
+	// and to SubmitWait callers whose task was dropped by ShutdownNow.
+	ErrPoolClosed = errors.New("concurrent: worker pool is shut down")
+
+	// ErrPoolFull is returned by Submit when the queue is full and the pool uses RejectPolicy.
+	ErrPoolFull = errors.New("concurrent: worker pool queue is full")
+)
+
+// Policy decides what Submit does when a bounded queue is full.
+type Policy int
+
+const (
+	BlockPolicy      Policy = iota // Wait until there is room in the queue
+	RejectPolicy                   // Fail with ErrPoolFull
+	CallerRunsPolicy               // Run the task on the submitting goroutine
+)
+
+// PoolOption configures a WorkerPool.
+type PoolOption func(*WorkerPool)
+
+// WithQueueSize bounds the number of tasks waiting for a worker.
+// A size of zero or less means the queue is unbounded, which is the default.
+func WithQueueSize(size int) PoolOption {
+	return func(p *WorkerPool) {
+		p.queueSize = max(size, 0)
+	}
+}
+
+// WithPolicy sets what Submit does when the queue is full. The default is BlockPolicy.
+func WithPolicy(policy Policy) PoolOption {
+	return func(p *WorkerPool) {
+		p.policy = policy
+	}
+}
+
+// WithRateLimiter makes workers start tasks no faster than limiter allows.
+// A task whose context is canceled while it waits for a permit fails with
+// the context's error without running.
+func WithRateLimiter(limiter RateLimiter) PoolOption {
+	return func(p *WorkerPool) {
+		p.limiter = limiter
+	}
+}
+
+// PoolStats is a snapshot of the state of a WorkerPool.
+type PoolStats struct {
+	Workers   int    // Target number of workers
+	Active    int    // Workers currently running a task
+	Queued    int    // Tasks waiting for a worker
+	Submitted uint64 // Tasks accepted so far
+	Completed uint64 // Tasks that returned without error
+	Failed    uint64 // Tasks that returned an error or panicked
+	Rejected  uint64 // Tasks refused because the queue was full
+}
+
+// poolTask is a queued task and, for SubmitWait, where to report its outcome.
+type poolTask struct {
+	job[struct{}]
+	ctx  context.Context
+	done chan error
+}
+
+// WorkerPool is a long-lived set of workers that run submitted tasks.
+// Unlike Parallel, the workers are started once and reused across submissions.
+// A WorkerPool must be created with NewWorkerPool and is safe for concurrent use.
+type WorkerPool struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond // Signaled when a task is queued or workers must exit
+	notFull  *sync.Cond // Signaled when a task leaves the queue or the pool closes
+
+	queue     []*poolTask
+	queueSize int
+	policy    Policy
+	limiter   RateLimiter
+
+	workers int // Target number of workers
+	running int // Worker goroutines alive
+	closed  bool
+	stats   PoolStats
+
+	ctx    context.Context // Canceled by ShutdownNow
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorkerPool starts a pool with the given number of workers.
+// If workers is less than one, a single worker is started.
+func NewWorkerPool(workers int, opts ...PoolOption) *WorkerPool {
+	p := &WorkerPool{}
+	p.notEmpty = sync.NewCond(&p.mu)
+	p.notFull = sync.NewCond(&p.mu)
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.mu.Lock()
+	p.resize(workers)
+	p.mu.Unlock()
+	return p
+}
+
+// Submit queues task to be run by a worker. The task's context is canceled by ShutdownNow.
+// Errors returned by the task are only counted in Stats; use SubmitWait to observe them.
+//
+// When the queue is full, Submit blocks, returns ErrPoolFull or runs the task itself,
+// depending on the pool's Policy. It returns ErrPoolClosed once the pool is shut down.
+func (p *WorkerPool) Submit(task func(context.Context) error) error {
+	ran, err := p.enqueue(context.Background(), &poolTask{ctx: p.ctx, job: job[struct{}]{task: wrapTask(task)}})
+	if ran {
+		return nil // The task's own error, counted in Stats
+	}
+	return err
+}
+
+// SubmitWait queues task and waits for it to finish, returning its error.
+// The task's context is derived from ctx and is also canceled by ShutdownNow.
+// If ctx is done before the task finishes, SubmitWait returns ctx.Err().
+func (p *WorkerPool) SubmitWait(ctx context.Context, task func(context.Context) error) error {
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(p.ctx, cancel)
+	defer stop()
+
+	t := &poolTask{ctx: taskCtx, done: make(chan error, 1), job: job[struct{}]{task: wrapTask(task)}}
+	ran, err := p.enqueue(ctx, t)
+	if err != nil || ran {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-t.done:
+		return err
+	}
+}
+
+// enqueue adds t to the queue, applying the pool's Policy if the queue is full.
+// It reports whether t was run on the calling goroutine, in which case the
+// returned error is the task's own.
+func (p *WorkerPool) enqueue(ctx context.Context, t *poolTask) (ran bool, err error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return false, ErrPoolClosed
+	}
+
+	if p.full() {
+		switch p.policy {
+		case RejectPolicy:
+			p.stats.Rejected++
+			p.mu.Unlock()
+			return false, ErrPoolFull
+		case CallerRunsPolicy:
+			p.stats.Submitted++
+			t.index = int(p.stats.Submitted - 1)
+			p.stats.Active++
+			p.mu.Unlock()
+
+			err := p.run(t)
+
+			p.mu.Lock()
+			p.stats.Active--
+			p.mu.Unlock()
+			return true, err
+		default:
+			// Wake up when ctx is done so that a blocked submitter can give up.
+			stop := context.AfterFunc(ctx, func() {
+				p.mu.Lock()
+				p.notFull.Broadcast()
+				p.mu.Unlock()
+			})
+			defer stop()
+
+			for p.full() && !p.closed && ctx.Err() == nil {
+				p.notFull.Wait()
+			}
+			if p.closed {
+				p.mu.Unlock()
+				return false, ErrPoolClosed
+			}
+			if err := ctx.Err(); err != nil {
+				p.mu.Unlock()
+				return false, err
+			}
+		}
+	}
+
+	p.stats.Submitted++
+	t.index = int(p.stats.Submitted - 1)
+	p.queue = append(p.queue, t)
+	p.notEmpty.Signal()
+	p.mu.Unlock()
+	return false, nil
+}
+
+// full reports whether a bounded queue has no room. p.mu must be held.
+func (p *WorkerPool) full() bool {
+	return p.queueSize > 0 && len(p.queue) >= p.queueSize
+}
+
+// Resize changes the number of workers. Extra workers exit after finishing
+// their current task. If workers is less than one, a single worker is kept.
+func (p *WorkerPool) Resize(workers int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.resize(workers)
+	}
+}
+
+// resize sets the target number of workers, starting any that are missing.
+// p.mu must be held.
+func (p *WorkerPool) resize(workers int) {
+	p.workers = max(workers, 1)
+	for p.running < p.workers {
+		p.running++
+		p.wg.Add(1)
+		go p.loop()
+	}
+	p.notEmpty.Broadcast()
+}
+
+// loop is the worker loop of the pool. It runs queued tasks until the pool
+// shuts down and the queue is empty, or the pool shrinks below this worker.
+//
+// It does not reuse worker: that loop drains a channel until it is closed and
+// shares one context, while pool workers must exit one at a time on Resize,
+// run each task with its own context and leave an unbounded queue in place
+// for ShutdownNow to drop. Tasks are still run the same way, with runTask.
+func (p *WorkerPool) loop() {
+	defer p.wg.Done()
+
+	p.mu.Lock()
+	for {
+		for len(p.queue) == 0 && !p.closed && p.running <= p.workers {
+			p.notEmpty.Wait()
+		}
+		if p.running > p.workers || len(p.queue) == 0 {
+			p.running--
+			p.mu.Unlock()
+			return
+		}
+
+		t := p.queue[0]
+		p.queue[0] = nil
+		p.queue = p.queue[1:]
+		p.stats.Active++
+		p.notFull.Signal()
+		p.mu.Unlock()
+
+		err := p.run(t)
+		if t.done != nil {
+			t.done <- err
+		}
+
+		p.mu.Lock()
+		p.stats.Active--
+	}
+}
+
+// run runs t, once the rate limiter allows it, and records its outcome.
+func (p *WorkerPool) run(t *poolTask) error {
+	var err error
+	if p.limiter != nil {
+		err = p.limiter.Wait(t.ctx)
+	}
+	if err == nil {
+		_, err = runTask(t.ctx, t.job)
+	}
+
+	p.mu.Lock()
+	if err != nil {
+		p.stats.Failed++
+	} else {
+		p.stats.Completed++
+	}
+	p.mu.Unlock()
+	return err
+}
+
+// Stats returns a snapshot of the pool's counters.
+func (p *WorkerPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Workers = p.workers
+	stats.Queued = len(p.queue)
+	return stats
+}
+
+// Shutdown stops accepting new tasks and waits for queued and running tasks
+// to finish. If ctx is done first, Shutdown returns ctx.Err() and the
+// remaining tasks keep running in the background.
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.close(false)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		p.cancel()
+		return nil
+	}
+}
+
+// ShutdownNow stops accepting new tasks, drops queued tasks, cancels the
+// context of running tasks and waits for them to return.
+// It returns the number of queued tasks that were dropped.
+func (p *WorkerPool) ShutdownNow() int {
+	dropped := p.close(true)
+	for _, t := range dropped {
+		if t.done != nil {
+			t.done <- ErrPoolClosed
+		}
+	}
+
+	p.cancel()
+	p.wg.Wait()
+	return len(dropped)
+}
+
+// close marks the pool as shut down and wakes up all waiters.
+// If drop is true, the queued tasks are removed and returned.
+func (p *WorkerPool) close(drop bool) (dropped []*poolTask) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	if drop {
+		dropped, p.queue = p.queue, nil
+	}
+	p.notEmpty.Broadcast()
+	p.notFull.Broadcast()
+	return dropped
+}
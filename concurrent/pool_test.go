@@ -0,0 +1,251 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestWorkerPoolSubmit(t *testing.T) {
+	pool := concurrent.NewWorkerPool(4)
+
+	var sum atomic.Int64
+	for i := 1; i <= 100; i++ {
+		err := pool.Submit(func(ctx context.Context) error {
+			sum.Add(int64(i))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("want nil error, got %v", err)
+		}
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if sum.Load() != 5050 {
+		t.Errorf("want 5050, got %d", sum.Load())
+	}
+
+	stats := pool.Stats()
+	if stats.Submitted != 100 || stats.Completed != 100 || stats.Queued != 0 || stats.Active != 0 {
+		t.Errorf("unexpected stats after shutdown: %+v", stats)
+	}
+
+	if err := pool.Submit(func(ctx context.Context) error { return nil }); !errors.Is(err, concurrent.ErrPoolClosed) {
+		t.Errorf("want %v, got %v", concurrent.ErrPoolClosed, err)
+	}
+}
+
+func TestWorkerPoolSubmitWait(t *testing.T) {
+	pool := concurrent.NewWorkerPool(2)
+	defer pool.ShutdownNow()
+
+	errFail := errors.New("fail")
+	err := pool.SubmitWait(context.Background(), func(ctx context.Context) error { return errFail })
+	if !errors.Is(err, errFail) {
+		t.Errorf("want %v, got %v", errFail, err)
+	}
+
+	err = pool.SubmitWait(context.Background(), func(ctx context.Context) error { panic("boom") })
+	var pe *concurrent.PanicError
+	if !errors.As(err, &pe) {
+		t.Errorf("want a *PanicError, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = pool.SubmitWait(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	pool.Shutdown(context.Background())
+	if stats := pool.Stats(); stats.Failed != 3 {
+		t.Errorf("want 3 failed tasks, got %+v", stats)
+	}
+}
+
+// blockPool returns a pool with one worker that is busy until release is closed.
+func blockPool(t *testing.T, opts ...concurrent.PoolOption) (*concurrent.WorkerPool, chan struct{}) {
+	t.Helper()
+	pool := concurrent.NewWorkerPool(1, opts...)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	err := pool.Submit(func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	<-started
+	return pool, release
+}
+
+func TestWorkerPoolRejectPolicy(t *testing.T) {
+	pool, release := blockPool(t, concurrent.WithQueueSize(1), concurrent.WithPolicy(concurrent.RejectPolicy))
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := pool.Submit(noop); err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if err := pool.Submit(noop); !errors.Is(err, concurrent.ErrPoolFull) {
+		t.Errorf("want %v, got %v", concurrent.ErrPoolFull, err)
+	}
+
+	stats := pool.Stats()
+	if stats.Active != 1 || stats.Queued != 1 || stats.Rejected != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	close(release)
+	pool.Shutdown(context.Background())
+}
+
+func TestWorkerPoolCallerRunsPolicy(t *testing.T) {
+	pool, release := blockPool(t, concurrent.WithQueueSize(1), concurrent.WithPolicy(concurrent.CallerRunsPolicy))
+	defer pool.ShutdownNow()
+
+	pool.Submit(func(ctx context.Context) error { return nil })
+
+	// Submit does not report the error of a task run on the caller.
+	errCaller := errors.New("caller")
+	var ranOnCaller bool
+	var active int
+	err := pool.Submit(func(ctx context.Context) error {
+		ranOnCaller = true
+		active = pool.Stats().Active
+		return errCaller
+	})
+	if err != nil || !ranOnCaller {
+		t.Errorf("want task to run on the caller, got %v %v", ranOnCaller, err)
+	}
+	if active != 2 {
+		t.Errorf("want the caller counted as active, got %d active", active)
+	}
+
+	// SubmitWait does.
+	err = pool.SubmitWait(context.Background(), func(ctx context.Context) error { return errCaller })
+	if !errors.Is(err, errCaller) {
+		t.Errorf("want %v, got %v", errCaller, err)
+	}
+	if stats := pool.Stats(); stats.Active != 1 || stats.Failed != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	close(release)
+}
+
+func TestWorkerPoolBlockPolicy(t *testing.T) {
+	pool, release := blockPool(t, concurrent.WithQueueSize(1))
+	defer pool.ShutdownNow()
+	noop := func(ctx context.Context) error { return nil }
+
+	pool.Submit(noop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.SubmitWait(ctx, noop); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	done := make(chan error)
+	go func() { done <- pool.Submit(noop) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("want Submit to block, returned %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
+
+func TestWorkerPoolShutdownNow(t *testing.T) {
+	pool, _ := blockPool(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- pool.SubmitWait(context.Background(), func(ctx context.Context) error { return nil })
+		}()
+	}
+
+	for pool.Stats().Queued < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if dropped := pool.ShutdownNow(); dropped != 3 {
+		t.Errorf("want 3 dropped tasks, got %d", dropped)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if !errors.Is(err, concurrent.ErrPoolClosed) {
+			t.Errorf("want %v, got %v", concurrent.ErrPoolClosed, err)
+		}
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	pool := concurrent.NewWorkerPool(1)
+	defer pool.ShutdownNow()
+
+	pool.Resize(4)
+	if got := pool.Stats().Workers; got != 4 {
+		t.Errorf("want 4 workers, got %d", got)
+	}
+
+	var active, peak atomic.Int32
+	release := make(chan struct{})
+	for range 4 {
+		pool.Submit(func(ctx context.Context) error {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-release
+			active.Add(-1)
+			return nil
+		})
+	}
+
+	for pool.Stats().Active < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	pool.Resize(1)
+	if got := pool.Stats().Workers; got != 1 {
+		t.Errorf("want 1 worker, got %d", got)
+	}
+	if peak.Load() != 4 {
+		t.Errorf("want 4 tasks to run at once, got %d", peak.Load())
+	}
+
+	if err := pool.SubmitWait(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
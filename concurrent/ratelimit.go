@@ -0,0 +1,236 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often events may happen.
+// Implementations are safe for concurrent use.
+type RateLimiter interface {
+	// Allow reports whether an event may happen now, consuming a permit if so.
+	Allow() bool
+
+	// Reserve claims the next permit and returns when it may be used.
+	// The caller must wait for Reservation.Delay before acting, or Cancel it.
+	Reserve() *Reservation
+
+	// Wait blocks until an event may happen, or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// Reservation is a permit claimed by RateLimiter.Reserve.
+type Reservation struct {
+	at     time.Time
+	clock  Clock
+	once   sync.Once
+	cancel func()
+}
+
+// Delay returns how long to wait before acting on the reservation.
+func (r *Reservation) Delay() time.Duration {
+	return max(r.at.Sub(r.clock.Now()), 0)
+}
+
+// Cancel gives the permit back if it is not due yet, so that other events may use it.
+// It is safe to call Cancel more than once.
+func (r *Reservation) Cancel() {
+	r.once.Do(func() {
+		if r.Delay() > 0 {
+			r.cancel()
+		}
+	})
+}
+
+// wait waits for r or for ctx to be done, in which case r is canceled.
+func (r *Reservation) wait(ctx context.Context) error {
+	d := r.Delay()
+	if d == 0 {
+		return nil
+	}
+
+	select {
+	case <-r.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// LimiterOption configures a RateLimiter.
+type LimiterOption func(*limiterOptions)
+
+type limiterOptions struct {
+	clock Clock
+}
+
+// WithLimiterClock makes the limiter read the time from clock instead of SystemClock.
+func WithLimiterClock(clock Clock) LimiterOption {
+	return func(o *limiterOptions) {
+		o.clock = clock
+	}
+}
+
+func newLimiterOptions(opts []LimiterOption) limiterOptions {
+	o := limiterOptions{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// TokenBucket is a RateLimiter that refills permits at a steady rate and
+// lets up to burst of them accumulate while idle.
+type TokenBucket struct {
+	mu     sync.Mutex
+	clock  Clock
+	rate   float64 // Permits added per second
+	burst  float64
+	tokens float64 // Negative while permits are reserved ahead of time
+	last   time.Time
+}
+
+// NewTokenBucket creates a limiter that allows rate events per second on
+// average and bursts of up to burst events. The bucket starts full.
+// If burst is less than one, it is one. rate must be positive.
+func NewTokenBucket(rate float64, burst int, opts ...LimiterOption) *TokenBucket {
+	if rate <= 0 {
+		panic("concurrent: rate must be positive")
+	}
+
+	o := newLimiterOptions(opts)
+	b := float64(max(burst, 1))
+	return &TokenBucket{clock: o.clock, rate: rate, burst: b, tokens: b, last: o.clock.Now()}
+}
+
+// refill adds the tokens earned since the last call. l.mu must be held.
+func (l *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.burst, l.tokens+elapsed.Seconds()*l.rate)
+		l.last = now
+	}
+}
+
+// Allow reports whether an event may happen now, consuming a permit if so.
+func (l *TokenBucket) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(l.clock.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Reserve claims the next permit and returns when it may be used.
+func (l *TokenBucket) Reserve() *Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.refill(now)
+	l.tokens--
+
+	at := now
+	if l.tokens < 0 {
+		at = now.Add(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	}
+	return &Reservation{at: at, clock: l.clock, cancel: func() {
+		l.mu.Lock()
+		l.refill(l.clock.Now())
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+	}}
+}
+
+// Wait blocks until an event may happen, or ctx is done.
+func (l *TokenBucket) Wait(ctx context.Context) error {
+	return l.Reserve().wait(ctx)
+}
+
+// SlidingWindow is a RateLimiter that allows at most limit events in any
+// window of time. Unlike a TokenBucket, it never allows a burst that would
+// exceed limit within a window.
+type SlidingWindow struct {
+	mu     sync.Mutex
+	clock  Clock
+	limit  int
+	window time.Duration
+	events []time.Time // Times of granted events, oldest first, some maybe in the future
+}
+
+// NewSlidingWindow creates a limiter that allows at most limit events per window.
+// If limit is less than one, it is one.
+func NewSlidingWindow(limit int, window time.Duration, opts ...LimiterOption) *SlidingWindow {
+	o := newLimiterOptions(opts)
+	return &SlidingWindow{clock: o.clock, limit: max(limit, 1), window: window}
+}
+
+// prune forgets events that fell out of the window ending at now. l.mu must be held.
+func (l *SlidingWindow) prune(now time.Time) {
+	i := 0
+	for i < len(l.events) && !l.events[i].After(now.Add(-l.window)) {
+		i++
+	}
+	l.events = l.events[i:]
+}
+
+// Allow reports whether an event may happen now, consuming a permit if so.
+func (l *SlidingWindow) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.prune(now)
+	if len(l.events) >= l.limit {
+		return false
+	}
+	l.events = append(l.events, now)
+	return true
+}
+
+// Reserve claims the next permit and returns when it may be used.
+func (l *SlidingWindow) Reserve() *Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.prune(now)
+
+	// The event may happen once the one limit places before it leaves the window.
+	at := now
+	if n := len(l.events); n >= l.limit {
+		at = l.events[n-l.limit].Add(l.window)
+	}
+	if n := len(l.events); n > 0 {
+		at = maxTime(at, l.events[n-1])
+	}
+	l.events = append(l.events, at)
+
+	return &Reservation{at: at, clock: l.clock, cancel: func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i := len(l.events) - 1; i >= 0; i-- {
+			if l.events[i].Equal(at) {
+				l.events = append(l.events[:i], l.events[i+1:]...)
+				break
+			}
+		}
+	}}
+}
+
+// Wait blocks until an event may happen, or ctx is done.
+func (l *SlidingWindow) Wait(ctx context.Context) error {
+	return l.Reserve().wait(ctx)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,212 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestTokenBucketAllow(t *testing.T) {
+	clock := newFakeClock()
+	l := concurrent.NewTokenBucket(10, 3, concurrent.WithLimiterClock(clock))
+
+	for i := range 3 {
+		if !l.Allow() {
+			t.Errorf("want burst event %d to be allowed", i)
+		}
+	}
+	if l.Allow() {
+		t.Error("want event beyond the burst to be denied")
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("want event to be allowed after one refill interval")
+	}
+	if l.Allow() {
+		t.Error("want only one token to be refilled")
+	}
+
+	// Idle time never accumulates more than the burst.
+	clock.Advance(time.Hour)
+	allowed := 0
+	for l.Allow() {
+		allowed++
+	}
+	if allowed != 3 {
+		t.Errorf("want 3 events after idling, got %d", allowed)
+	}
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	clock := newFakeClock()
+	l := concurrent.NewTokenBucket(2, 1, concurrent.WithLimiterClock(clock))
+
+	if d := l.Reserve().Delay(); d != 0 {
+		t.Errorf("want no delay, got %v", d)
+	}
+	second := l.Reserve()
+	if d := second.Delay(); d != 500*time.Millisecond {
+		t.Errorf("want 500ms, got %v", d)
+	}
+	if d := l.Reserve().Delay(); d != time.Second {
+		t.Errorf("want 1s, got %v", d)
+	}
+
+	// Canceling gives back the second permit, so the next one is due sooner.
+	second.Cancel()
+	second.Cancel()
+	if d := l.Reserve().Delay(); d != time.Second {
+		t.Errorf("want 1s, got %v", d)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	clock := newFakeClock()
+	l := concurrent.NewSlidingWindow(2, time.Second, concurrent.WithLimiterClock(clock))
+
+	if !l.Allow() {
+		t.Error("want first event to be allowed")
+	}
+	clock.Advance(400 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("want second event to be allowed")
+	}
+	if l.Allow() {
+		t.Error("want third event in the window to be denied")
+	}
+
+	if d := l.Reserve().Delay(); d != 600*time.Millisecond {
+		t.Errorf("want 600ms, got %v", d)
+	}
+	if d := l.Reserve().Delay(); d != time.Second {
+		t.Errorf("want 1s, got %v", d)
+	}
+
+	clock.Advance(600 * time.Millisecond)
+	if l.Allow() {
+		t.Error("want reserved events to count against the window")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiters := map[string]func(concurrent.Clock) concurrent.RateLimiter{
+		"TokenBucket": func(c concurrent.Clock) concurrent.RateLimiter {
+			return concurrent.NewTokenBucket(1, 1, concurrent.WithLimiterClock(c))
+		},
+		"SlidingWindow": func(c concurrent.Clock) concurrent.RateLimiter {
+			return concurrent.NewSlidingWindow(1, time.Second, concurrent.WithLimiterClock(c))
+		},
+	}
+
+	for name, newLimiter := range limiters {
+		t.Run(name, func(t *testing.T) {
+			clock := newFakeClock()
+			l := newLimiter(clock)
+			ctx := context.Background()
+
+			if err := l.Wait(ctx); err != nil {
+				t.Fatalf("want <nil>, got %v", err)
+			}
+
+			done := make(chan error, 1)
+			go func() { done <- l.Wait(ctx) }()
+			for clock.Waiters() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			select {
+			case err := <-done:
+				t.Fatalf("want Wait to block, got %v", err)
+			default:
+			}
+
+			clock.Advance(time.Second)
+			if err := <-done; err != nil {
+				t.Errorf("want <nil>, got %v", err)
+			}
+
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			if err := l.Wait(canceled); !errors.Is(err, context.Canceled) {
+				t.Errorf("want %v, got %v", context.Canceled, err)
+			}
+		})
+	}
+}
+
+func TestParallelContextRateLimit(t *testing.T) {
+	clock := newFakeClock()
+	limiter := concurrent.NewTokenBucket(1, 2, concurrent.WithLimiterClock(clock))
+
+	var started atomic.Int32
+	tasks := make([]func(context.Context) error, 4)
+	for i := range tasks {
+		tasks[i] = func(context.Context) error {
+			started.Add(1)
+			return nil
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- concurrent.ParallelContext(context.Background(), tasks, 4, concurrent.RateLimit(limiter))
+	}()
+
+	// The burst starts two tasks right away; each later one needs a second.
+	for clock.Waiters() == 0 || started.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if n := started.Load(); n != 2 {
+		t.Errorf("want 2 tasks started, got %d", n)
+	}
+
+	for started.Load() < 4 {
+		if clock.Waiters() > 0 {
+			clock.Advance(time.Second)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("want <nil>, got %v", err)
+	}
+}
+
+func TestWorkerPoolRateLimiter(t *testing.T) {
+	clock := newFakeClock()
+	limiter := concurrent.NewSlidingWindow(1, time.Minute, concurrent.WithLimiterClock(clock))
+	p := concurrent.NewWorkerPool(2, concurrent.WithRateLimiter(limiter))
+	defer p.ShutdownNow()
+
+	ctx := context.Background()
+	if err := p.SubmitWait(ctx, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("want <nil>, got %v", err)
+	}
+
+	// The second task waits for the window and fails if its context is done first.
+	timeout, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- p.SubmitWait(timeout, func(context.Context) error { return nil })
+	}()
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v, got %v", context.Canceled, err)
+	}
+
+	// Wait for the worker to give the permit back.
+	for p.Stats().Failed == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(time.Minute)
+	if err := p.SubmitWait(ctx, func(context.Context) error { return nil }); err != nil {
+		t.Errorf("want <nil>, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultRetryAttempts is the number of attempts made when RetryPolicy.MaxAttempts is zero.
+const DefaultRetryAttempts = 3
+
+// Backoff returns how long to wait before the next attempt, given the number
+// of attempts made so far and the previous wait, which is zero at first.
+type Backoff func(attempt int, last time.Duration) time.Duration
+
+// ConstantBackoff waits d between attempts.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff waits base after the first attempt and doubles the wait
+// after every further attempt, up to limit.
+func ExponentialBackoff(base, limit time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < limit; i++ {
+			d *= 2
+		}
+		return min(d, limit)
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random duration between base and three
+// times the previous wait, up to limit. The randomness spreads out retries
+// from many clients that failed at the same time.
+func DecorrelatedJitterBackoff(base, limit time.Duration) Backoff {
+	return func(_ int, last time.Duration) time.Duration {
+		upper := max(last*3, base)
+		d := base
+		if upper > base {
+			d += time.Duration(rand.Int64N(int64(upper - base)))
+		}
+		return min(d, limit)
+	}
+}
+
+// RetryPolicy configures how Retry and the Retries option retry failing tasks.
+// The zero value makes DefaultRetryAttempts attempts without waiting.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// If zero, DefaultRetryAttempts is used. If negative, attempts are only
+	// limited by MaxElapsed and the context.
+	MaxAttempts int
+
+	// Backoff decides how long to wait between attempts. If nil, there is no wait.
+	Backoff Backoff
+
+	// MaxElapsed, if positive, stops retrying once the next attempt would
+	// start more than MaxElapsed after the first one.
+	MaxElapsed time.Duration
+
+	// Retryable reports whether an error is worth retrying. If nil, every
+	// error is retried except a *PanicError.
+	Retryable func(error) bool
+
+	// Clock is used to measure elapsed time and wait. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// RetryError is returned when a task still fails after being retried.
+type RetryError struct {
+	Attempts int   // Number of attempts made
+	Err      error // Error of the last attempt
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("concurrent: giving up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry calls fn until it succeeds, following policy. fn receives ctx.
+// Retry stops early if ctx is done, without starting another attempt, and
+// returns ctx.Err() joined with the error of the last attempt.
+// If fn panics, the panic is reported as a *PanicError, which is not retried
+// unless policy.Retryable says so.
+// If fn was retried and the last attempt failed too, Retry returns a
+// *RetryError wrapping the last error.
+func Retry[T any](ctx context.Context, policy RetryPolicy, fn func(context.Context) (T, error)) (T, error) {
+	value, _, err := retryTask(ctx, &policy, job[T]{task: fn})
+	return value, err
+}
+
+// retryTask runs j until it succeeds, p gives up or ctx is done, and returns
+// the number of attempts made. If p is nil, j is run once.
+func retryTask[T any](ctx context.Context, p *RetryPolicy, j job[T]) (value T, attempts int, err error) {
+	if p == nil {
+		value, err = runTask(ctx, j)
+		return value, 1, err
+	}
+
+	clock := p.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultRetryAttempts
+	}
+
+	start := clock.Now()
+	var wait time.Duration
+	for {
+		value, err = runTask(ctx, j)
+		attempts++
+		if err == nil {
+			return value, attempts, nil
+		}
+		if !p.retryable(err) || attempts == maxAttempts {
+			break
+		}
+		if ctx.Err() != nil {
+			return value, attempts, canceled(ctx, err)
+		}
+
+		if p.Backoff != nil {
+			wait = p.Backoff(attempts, wait)
+		}
+		if p.MaxElapsed > 0 && clock.Now().Add(wait).Sub(start) > p.MaxElapsed {
+			break
+		}
+		if wait > 0 {
+			select {
+			case <-clock.After(wait):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			return value, attempts, canceled(ctx, err)
+		}
+	}
+	if attempts > 1 {
+		err = &RetryError{Attempts: attempts, Err: err}
+	}
+	return value, attempts, err
+}
+
+// canceled returns the error of a task that stopped being retried because ctx
+// is done: ctx.Err() joined with the error of the last attempt, unless the
+// attempt already failed with it.
+func canceled(ctx context.Context, err error) error {
+	if errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return errors.Join(ctx.Err(), err)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	var panicErr *PanicError
+	return !errors.As(err, &panicErr)
+}
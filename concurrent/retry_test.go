@@ -0,0 +1,201 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+// failTimes returns a task that fails n times with err before returning value.
+func failTimes[T any](n int, value T, err error) (func(context.Context) (T, error), *atomic.Int32) {
+	var calls atomic.Int32
+	return func(context.Context) (T, error) {
+		if int(calls.Add(1)) <= n {
+			var zero T
+			return zero, err
+		}
+		return value, nil
+	}, &calls
+}
+
+func TestRetry(t *testing.T) {
+	boom := errors.New("boom")
+	task, calls := failTimes(2, "ok", boom)
+
+	v, err := concurrent.Retry(context.Background(), concurrent.RetryPolicy{}, task)
+	if v != "ok" || err != nil || calls.Load() != 3 {
+		t.Errorf("want ok <nil> after 3 calls, got %q %v after %d", v, err, calls.Load())
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	boom := errors.New("boom")
+	task, calls := failTimes(10, 0, boom)
+
+	_, err := concurrent.Retry(context.Background(), concurrent.RetryPolicy{MaxAttempts: 4}, task)
+	var retryErr *concurrent.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 4 || !errors.Is(err, boom) {
+		t.Errorf("want RetryError after 4 attempts wrapping %v, got %v", boom, err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("want 4 calls, got %d", calls.Load())
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	permanent := errors.New("permanent")
+	task, calls := failTimes(10, 0, permanent)
+
+	policy := concurrent.RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return !errors.Is(err, permanent) },
+	}
+	if _, err := concurrent.Retry(context.Background(), policy, task); err != permanent {
+		t.Errorf("want %v, got %v", permanent, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("want 1 call, got %d", calls.Load())
+	}
+
+	// Panics are not retried by default.
+	var panics atomic.Int32
+	_, err := concurrent.Retry(context.Background(), concurrent.RetryPolicy{}, func(context.Context) (int, error) {
+		panics.Add(1)
+		panic("oops")
+	})
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) || panics.Load() != 1 {
+		t.Errorf("want a single PanicError, got %v after %d calls", err, panics.Load())
+	}
+}
+
+func TestRetryBackoffAndMaxElapsed(t *testing.T) {
+	clock := newFakeClock()
+	boom := errors.New("boom")
+	task, calls := failTimes(10, 0, boom)
+
+	policy := concurrent.RetryPolicy{
+		MaxAttempts: -1,
+		Backoff:     concurrent.ExponentialBackoff(time.Second, time.Minute),
+		MaxElapsed:  5 * time.Second,
+		Clock:       clock,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := concurrent.Retry(context.Background(), policy, task)
+		done <- err
+	}()
+
+	// Waits of 1s and 2s fit in 5s; the next wait of 4s does not.
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second} {
+		for clock.Waiters() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(wait)
+	}
+
+	var retryErr *concurrent.RetryError
+	if err := <-done; !errors.As(err, &retryErr) || retryErr.Attempts != 3 {
+		t.Errorf("want RetryError after 3 attempts, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("want 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	boom := errors.New("boom")
+
+	policy := concurrent.RetryPolicy{MaxAttempts: -1, Backoff: concurrent.ConstantBackoff(time.Hour)}
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	task, calls := failTimes(10, 0, boom)
+	_, err := concurrent.Retry(ctx, policy, task)
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, boom) {
+		t.Errorf("want %v joined with %v, got %v", context.Canceled, boom, err)
+	}
+	var retryErr *concurrent.RetryError
+	if errors.As(err, &retryErr) {
+		t.Errorf("want cancellation not reported as giving up, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("want no attempt after ctx is done, got %d calls", calls.Load())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	exp := concurrent.ExponentialBackoff(time.Second, 10*time.Second)
+	var last time.Duration
+	for i, want := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		last = exp(i+1, last)
+		if last != want*time.Second {
+			t.Errorf("attempt %d: want %v, got %v", i+1, want*time.Second, last)
+		}
+	}
+
+	if d := concurrent.ConstantBackoff(time.Second)(5, 0); d != time.Second {
+		t.Errorf("want 1s, got %v", d)
+	}
+
+	jitter := concurrent.DecorrelatedJitterBackoff(time.Second, 30*time.Second)
+	last = 0
+	for i := range 100 {
+		next := jitter(i+1, last)
+		if next < time.Second || next > 30*time.Second || next > max(3*last, time.Second) {
+			t.Fatalf("want wait in [1s, min(30s, 3*%v)], got %v", last, next)
+		}
+		last = next
+	}
+}
+
+func TestParallelResultsRetries(t *testing.T) {
+	boom := errors.New("boom")
+	flaky, _ := failTimes(2, 1, boom)
+	broken, _ := failTimes(10, 0, boom)
+	tasks := []func(context.Context) (int, error){
+		func(context.Context) (int, error) { return 0, nil },
+		flaky,
+		broken,
+	}
+
+	results := concurrent.ParallelResults(context.Background(), tasks, 3,
+		concurrent.Retries(concurrent.RetryPolicy{MaxAttempts: 3}))
+
+	for i, want := range []int{1, 3, 3} {
+		if results[i].Attempts != want {
+			t.Errorf("task %d: want %d attempts, got %d", i, want, results[i].Attempts)
+		}
+	}
+	if results[1].Err != nil || results[1].Value != 1 {
+		t.Errorf("want flaky task to succeed, got %v %v", results[1].Value, results[1].Err)
+	}
+	var retryErr *concurrent.RetryError
+	if !errors.As(results[2].Err, &retryErr) {
+		t.Errorf("want RetryError, got %v", results[2].Err)
+	}
+}
+
+func TestParallelContextRetries(t *testing.T) {
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	tasks := []func(context.Context) error{
+		func(context.Context) error {
+			if calls.Add(1) < 2 {
+				return boom
+			}
+			return nil
+		},
+	}
+
+	err := concurrent.ParallelContext(context.Background(), tasks, 1,
+		concurrent.Retries(concurrent.RetryPolicy{MaxAttempts: 2}))
+	if err != nil || calls.Load() != 2 {
+		t.Errorf("want <nil> after 2 calls, got %v after %d", err, calls.Load())
+	}
+}
@@ -0,0 +1,123 @@
+package concurrent
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrWeightTooLarge is returned when acquiring more than the size of a Semaphore.
+var ErrWeightTooLarge = errors.New("concurrent: weight exceeds semaphore size")
+
+// Semaphore is a weighted semaphore: it hands out up to size units, and each
+// caller may claim several at once. Waiters are served in the order they
+// arrived, so a large request is not starved by a stream of small ones.
+//
+// A Semaphore must be created with NewSemaphore and is safe for concurrent use.
+type Semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	held    int64
+	waiters list.List // Of *semWaiter, in arrival order
+}
+
+type semWaiter struct {
+	n     int64
+	ready chan struct{} // Closed once the units are granted
+}
+
+// NewSemaphore creates a semaphore with the given number of units.
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{size: size}
+}
+
+// Acquire claims n units, blocking until they are available or ctx is done.
+// It returns ErrWeightTooLarge if n exceeds the size of the semaphore,
+// and ctx.Err() if ctx is done first, in which case nothing is claimed.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	if n < 0 {
+		panic("concurrent: negative semaphore weight")
+	}
+
+	s.mu.Lock()
+	if n > s.size {
+		s.mu.Unlock()
+		return ErrWeightTooLarge
+	}
+	if s.size-s.held >= n && s.waiters.Len() == 0 {
+		s.held += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := &semWaiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		// Granted while ctx was being canceled; give the units back.
+		s.held -= n
+	default:
+		s.waiters.Remove(elem)
+	}
+	// Waiters queued behind this one may fit now.
+	s.notify()
+	return ctx.Err()
+}
+
+// TryAcquire claims n units only if they are available right away,
+// and reports whether it did.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	if n < 0 {
+		panic("concurrent: negative semaphore weight")
+	}
+
+	s.mu.Lock()
+	ok := s.size-s.held >= n && s.waiters.Len() == 0
+	if ok {
+		s.held += n
+	}
+	s.mu.Unlock()
+	return ok
+}
+
+// Release gives back n units. It panics if more units are released than are held.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.held -= n
+	if s.held < 0 {
+		panic("concurrent: semaphore released more than held")
+	}
+	s.notify()
+}
+
+// notify grants units to waiters in arrival order, stopping at the first one
+// that does not fit. s.mu must be held.
+func (s *Semaphore) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+		w := front.Value.(*semWaiter)
+		if s.size-s.held < w.n {
+			return
+		}
+		s.held += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
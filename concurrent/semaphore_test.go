@@ -0,0 +1,191 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestSemaphore(t *testing.T) {
+	s := concurrent.NewSemaphore(10)
+	ctx := context.Background()
+
+	if err := s.Acquire(ctx, 7); err != nil {
+		t.Fatalf("want <nil>, got %v", err)
+	}
+	if s.TryAcquire(4) {
+		t.Error("want TryAcquire(4) to fail with 3 units free")
+	}
+	if !s.TryAcquire(3) {
+		t.Error("want TryAcquire(3) to succeed")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		if err := s.Acquire(ctx, 5); err != nil {
+			t.Errorf("want <nil>, got %v", err)
+		}
+		close(acquired)
+	}()
+
+	s.Release(3)
+	select {
+	case <-acquired:
+		t.Fatal("want Acquire(5) to wait with 3 units free")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.Release(7)
+	<-acquired
+	s.Release(5)
+
+	if err := s.Acquire(ctx, 11); err != concurrent.ErrWeightTooLarge {
+		t.Errorf("want %v, got %v", concurrent.ErrWeightTooLarge, err)
+	}
+}
+
+func TestSemaphoreFIFO(t *testing.T) {
+	s := concurrent.NewSemaphore(2)
+	ctx := context.Background()
+	s.Acquire(ctx, 2)
+
+	// A large waiter at the head is not overtaken by smaller ones.
+	large := make(chan struct{})
+	go func() {
+		s.Acquire(ctx, 2)
+		close(large)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	if s.TryAcquire(1) {
+		t.Error("want TryAcquire to fail while others are waiting")
+	}
+
+	s.Release(1)
+	select {
+	case <-large:
+		t.Fatal("want large waiter to need both units")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.Release(1)
+	<-large
+}
+
+func TestSemaphoreAcquireCanceled(t *testing.T) {
+	s := concurrent.NewSemaphore(1)
+	s.Acquire(context.Background(), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	// The canceled waiter did not claim anything.
+	s.Release(1)
+	if !s.TryAcquire(1) {
+		t.Error("want the unit to be free")
+	}
+}
+
+func TestSemaphoreReleaseTooMuch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want Release to panic")
+		}
+	}()
+	concurrent.NewSemaphore(1).Release(1)
+}
+
+func TestParallelWeighted(t *testing.T) {
+	const capacity = 10
+	weights := []int64{6, 4, 5, 5, 10, 1, 2, 3}
+
+	var inUse, peak atomic.Int64
+	tasks := make([]func(context.Context) error, len(weights))
+	for i, w := range weights {
+		tasks[i] = func(context.Context) error {
+			n := inUse.Add(w)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inUse.Add(-w)
+			return nil
+		}
+	}
+
+	if err := concurrent.ParallelWeighted(context.Background(), tasks, weights, capacity); err != nil {
+		t.Fatalf("want <nil>, got %v", err)
+	}
+	if peak.Load() > capacity {
+		t.Errorf("want at most %d units in use, got %d", capacity, peak.Load())
+	}
+}
+
+func TestParallelWeightedGoroutines(t *testing.T) {
+	const n = 10000
+	before := runtime.NumGoroutine()
+
+	var peak atomic.Int64
+	tasks := make([]func(context.Context) error, n)
+	weights := make([]int64, n)
+	for i := range tasks {
+		weights[i] = 1
+		tasks[i] = func(context.Context) error {
+			g := int64(runtime.NumGoroutine())
+			for {
+				p := peak.Load()
+				if g <= p || peak.CompareAndSwap(p, g) {
+					break
+				}
+			}
+			return nil
+		}
+	}
+
+	if err := concurrent.ParallelWeighted(context.Background(), tasks, weights, 4); err != nil {
+		t.Fatalf("want <nil>, got %v", err)
+	}
+	// Only as many workers as tasks fit in the capacity should be started.
+	if extra := peak.Load() - int64(before); extra > 50 {
+		t.Errorf("want a handful of goroutines, got %d extra", extra)
+	}
+}
+
+func TestParallelWeightedErrors(t *testing.T) {
+	boom := errors.New("boom")
+	ran := false
+	tasks := []func(context.Context) error{
+		func(context.Context) error { return boom },
+		func(context.Context) error {
+			ran = true
+			return nil
+		},
+	}
+
+	err := concurrent.ParallelWeighted(context.Background(), tasks, []int64{1, 20}, 10)
+	if !errors.Is(err, concurrent.ErrWeightTooLarge) || ran {
+		t.Errorf("want %v without running tasks, got %v", concurrent.ErrWeightTooLarge, err)
+	}
+
+	err = concurrent.ParallelWeighted(context.Background(), tasks[:1], []int64{1}, 10)
+	if err != boom {
+		t.Errorf("want %v, got %v", boom, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("want mismatched lengths to panic")
+		}
+	}()
+	concurrent.ParallelWeighted(context.Background(), tasks, []int64{1}, 10)
+}
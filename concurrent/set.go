@@ -1,6 +1,11 @@
 package concurrent
 
-import "sync"
+import (
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+)
 
 // Set is a concurrent set, safe for read and write operations.
 // Guarded by a read-write mutex.
@@ -16,6 +21,24 @@ func NewSet[K comparable]() *Set[K] {
 	}
 }
 
+// NewSetFrom creates a new concurrent set containing the given elements.
+func NewSetFrom[K comparable](keys ...K) *Set[K] {
+	s := &Set[K]{m: make(map[K]struct{}, len(keys))}
+	for _, k := range keys {
+		s.m[k] = struct{}{}
+	}
+	return s
+}
+
+// NewSetFromSeq creates a new concurrent set containing the elements of seq.
+func NewSetFromSeq[K comparable](seq iter.Seq[K]) *Set[K] {
+	s := NewSet[K]()
+	for k := range seq {
+		s.m[k] = struct{}{}
+	}
+	return s
+}
+
 // Add adds the given element to the set.
 func (s *Set[K]) Add(key K) {
 	s.mu.Lock()
@@ -30,6 +53,53 @@ func (s *Set[K]) Remove(key K) {
 	s.mu.Unlock()
 }
 
+// AddAll adds all the given elements to the set under a single lock.
+func (s *Set[K]) AddAll(keys ...K) {
+	s.mu.Lock()
+	for _, k := range keys {
+		s.m[k] = struct{}{}
+	}
+	s.mu.Unlock()
+}
+
+// AddSeq adds the elements of seq to the set under a single lock.
+// seq is drained before the lock is taken, so it may safely read from s.
+func (s *Set[K]) AddSeq(seq iter.Seq[K]) {
+	s.AddAll(slices.Collect(seq)...)
+}
+
+// RemoveAll removes all the given elements from the set under a single lock.
+func (s *Set[K]) RemoveAll(keys ...K) {
+	s.mu.Lock()
+	for _, k := range keys {
+		delete(s.m, k)
+	}
+	s.mu.Unlock()
+}
+
+// RemoveSeq removes the elements of seq from the set under a single lock.
+// seq is drained before the lock is taken, so it may safely read from s.
+func (s *Set[K]) RemoveSeq(seq iter.Seq[K]) {
+	s.RemoveAll(slices.Collect(seq)...)
+}
+
+// RemoveIf removes every element that satisfies fn under a single lock
+// and returns the number of elements removed.
+// fn runs under the set's write lock and must not call methods on s.
+func (s *Set[K]) RemoveIf(fn func(K) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for k := range s.m {
+		if fn(k) {
+			delete(s.m, k)
+			removed++
+		}
+	}
+	return removed
+}
+
 // Contains checks if the set contains the given element.
 func (s *Set[K]) Contains(key K) bool {
 	s.mu.RLock()
@@ -38,6 +108,34 @@ func (s *Set[K]) Contains(key K) bool {
 	return ok
 }
 
+// ContainsAll checks if the set contains every one of the given elements.
+// It returns true if no elements are given.
+func (s *Set[K]) ContainsAll(keys ...K) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range keys {
+		if _, ok := s.m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny checks if the set contains at least one of the given elements.
+// It returns false if no elements are given.
+func (s *Set[K]) ContainsAny(keys ...K) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range keys {
+		if _, ok := s.m[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Len returns the number of elements in the set.
 func (s *Set[K]) Len() int {
 	s.mu.RLock()
@@ -58,104 +156,178 @@ func (s *Set[K]) Values() []K {
 	return values
 }
 
+// snapshot returns a copy of the elements of the set, taken under the read lock.
+//
+// Binary operations compare a snapshot of one set against the other while
+// holding at most one lock at a time. This avoids lock-ordering deadlocks
+// when a.Op(b) races with b.Op(a), and recursive read locking when s == other.
+func (s *Set[K]) snapshot() map[K]struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.m)
+}
+
+// All returns an iterator over a snapshot of the elements in the set.
+//
+// The snapshot is copied under the read lock, and no lock is held while yielding,
+// so the loop body may freely read and write the set. Changes made during the
+// iteration are not reflected in it. Use AllLocked to avoid the copy.
+func (s *Set[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s.snapshot() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// AllLocked returns an iterator over the elements in the set that holds
+// the read lock for the whole iteration.
+//
+// The loop body must not write to the set, or it will deadlock. Long loop bodies
+// also block writers for their whole duration. Prefer All unless the copy matters.
+func (s *Set[K]) AllLocked() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		for k := range s.m {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
 // Union returns a new set with all elements from both sets.
 func (s *Set[K]) Union(other *Set[K]) *Set[K] {
-	result := NewSet[K]()
-	s.mu.RLock()
-	other.mu.RLock()
+	result := other.snapshot()
 
+	s.mu.RLock()
 	defer s.mu.RUnlock()
-	defer other.mu.RUnlock()
 	for k := range s.m {
-		result.Add(k)
-	}
-	for k := range other.m {
-		result.Add(k)
+		result[k] = struct{}{}
 	}
-
-	return result
+	return &Set[K]{m: result}
 }
 
 // Intersection returns a new set with elements that are in both sets.
 func (s *Set[K]) Intersection(other *Set[K]) *Set[K] {
-	result := NewSet[K]()
-	s.mu.RLock()
-	other.mu.RLock()
+	theirs := other.snapshot()
+	result := make(map[K]struct{})
 
+	s.mu.RLock()
 	defer s.mu.RUnlock()
-	defer other.mu.RUnlock()
-
 	for k := range s.m {
-		if other.Contains(k) {
-			result.Add(k)
+		if _, ok := theirs[k]; ok {
+			result[k] = struct{}{}
 		}
 	}
-	return result
+	return &Set[K]{m: result}
 }
 
 // Difference returns a new set with elements that are in the first set but not in the second set.
 func (s *Set[K]) Difference(other *Set[K]) *Set[K] {
-	result := NewSet[K]()
-	s.mu.RLock()
-	other.mu.RLock()
+	theirs := other.snapshot()
+	result := make(map[K]struct{})
 
+	s.mu.RLock()
 	defer s.mu.RUnlock()
-	defer other.mu.RUnlock()
-
 	for k := range s.m {
-		if !other.Contains(k) {
-			result.Add(k)
+		if _, ok := theirs[k]; !ok {
+			result[k] = struct{}{}
 		}
 	}
-	return result
+	return &Set[K]{m: result}
 }
 
 // SymmetricDifference returns a new set with elements that are in one of the sets but not in both.
 func (s *Set[K]) SymmetricDifference(other *Set[K]) *Set[K] {
-	result := NewSet[K]()
-	s.mu.RLock()
-	other.mu.RLock()
+	result := other.snapshot()
 
+	s.mu.RLock()
 	defer s.mu.RUnlock()
-	defer other.mu.RUnlock()
-
 	for k := range s.m {
-		if !other.Contains(k) {
-			result.Add(k)
+		if _, ok := result[k]; ok {
+			delete(result, k)
+		} else {
+			result[k] = struct{}{}
 		}
 	}
-	for k := range other.m {
-		if !s.Contains(k) {
-			result.Add(k)
-		}
-	}
-	return result
+	return &Set[K]{m: result}
 }
 
 // IsSubset checks if the set is a subset of the other set.
 func (s *Set[K]) IsSubset(other *Set[K]) bool {
+	theirs := other.snapshot()
+
 	s.mu.RLock()
-	other.mu.RLock()
+	defer s.mu.RUnlock()
+	return isSubset(s.m, theirs)
+}
+
+// IsSuperset checks if the set is a superset of the other set.
+func (s *Set[K]) IsSuperset(other *Set[K]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal checks if the set is equal to the other set.
+// Both sets are compared as of a single snapshot of other.
+func (s *Set[K]) Equal(other *Set[K]) bool {
+	theirs := other.snapshot()
 
+	s.mu.RLock()
 	defer s.mu.RUnlock()
-	defer other.mu.RUnlock()
+	return len(s.m) == len(theirs) && isSubset(s.m, theirs)
+}
 
-	for k := range s.m {
-		if !other.Contains(k) {
+// isSubset reports whether every element of a is in b.
+func isSubset[K comparable](a, b map[K]struct{}) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
 			return false
 		}
 	}
 	return true
 }
 
-// IsSuperset checks if the set is a superset of the other set.
-func (s *Set[K]) IsSuperset(other *Set[K]) bool {
-	return other.IsSubset(s)
+// UnionWith adds all elements of other to the set, in place.
+func (s *Set[K]) UnionWith(other *Set[K]) {
+	theirs := other.snapshot()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range theirs {
+		s.m[k] = struct{}{}
+	}
 }
 
-// Equal checks if the set is equal to the other set.
-func (s *Set[K]) Equal(other *Set[K]) bool {
-	return s.IsSubset(other) && s.IsSuperset(other)
+// IntersectWith removes the elements that are not in other from the set, in place.
+func (s *Set[K]) IntersectWith(other *Set[K]) {
+	theirs := other.snapshot()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range s.m {
+		if _, ok := theirs[k]; !ok {
+			delete(s.m, k)
+		}
+	}
+}
+
+// SubtractWith removes the elements of other from the set, in place.
+func (s *Set[K]) SubtractWith(other *Set[K]) {
+	theirs := other.snapshot()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k := range theirs {
+		delete(s.m, k)
+	}
 }
 
 // Clear removes all elements from the set.
@@ -167,12 +339,5 @@ func (s *Set[K]) Clear() {
 
 // Clone returns a new set with a copy of all elements.
 func (s *Set[K]) Clone() *Set[K] {
-	result := NewSet[K]()
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	for k := range s.m {
-		result.Add(k)
-	}
-	return result
+	return &Set[K]{m: s.snapshot()}
 }
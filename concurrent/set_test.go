@@ -1,7 +1,10 @@
 package concurrent_test
 
 import (
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/abiiranathan/fn/concurrent"
 )
@@ -202,3 +205,196 @@ func TestSetClear(t *testing.T) {
 		t.Errorf("want 0 items, got %d", m.Len())
 	}
 }
+
+func TestSetEqual(t *testing.T) {
+	m1 := concurrent.NewSet[string]()
+	m1.Add("one")
+	m1.Add("two")
+
+	m2 := m1.Clone()
+	if !m1.Equal(m2) || !m1.Equal(m1) {
+		t.Error("want sets to be equal")
+	}
+
+	m2.Add("three")
+	if m1.Equal(m2) || m2.Equal(m1) {
+		t.Error("want sets to differ")
+	}
+}
+
+// TestSetAlgebraStress runs every binary operation in both directions and
+// against the set itself while writers keep mutating both sets.
+// Run with -race; a lock-ordering bug shows up as a timeout.
+func TestSetAlgebraStress(t *testing.T) {
+	a := concurrent.NewSet[int]()
+	b := concurrent.NewSet[int]()
+	for i := range 100 {
+		a.Add(i)
+		b.Add(i + 50)
+	}
+
+	ops := map[string]func(x, y *concurrent.Set[int]){
+		"Union":               func(x, y *concurrent.Set[int]) { x.Union(y) },
+		"Intersection":        func(x, y *concurrent.Set[int]) { x.Intersection(y) },
+		"Difference":          func(x, y *concurrent.Set[int]) { x.Difference(y) },
+		"SymmetricDifference": func(x, y *concurrent.Set[int]) { x.SymmetricDifference(y) },
+		"IsSubset":            func(x, y *concurrent.Set[int]) { x.IsSubset(y) },
+		"IsSuperset":          func(x, y *concurrent.Set[int]) { x.IsSuperset(y) },
+		"Equal":               func(x, y *concurrent.Set[int]) { x.Equal(y) },
+		"UnionWith":           func(x, y *concurrent.Set[int]) { x.UnionWith(y) },
+		"IntersectWith":       func(x, y *concurrent.Set[int]) { x.IntersectWith(y) },
+		"SubtractWith":        func(x, y *concurrent.Set[int]) { x.SubtractWith(y) },
+	}
+
+	done := make(chan struct{})
+	stop := make(chan struct{})
+
+	var writers sync.WaitGroup
+	for _, s := range []*concurrent.Set[int]{a, b} {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				s.Add(i % 200)
+				s.Remove((i + 100) % 200)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for _, op := range ops {
+			for _, pair := range [][2]*concurrent.Set[int]{{a, b}, {b, a}, {a, a}} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 200 {
+						op(pair[0], pair[1])
+					}
+				}()
+			}
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("set operations deadlocked")
+	}
+	close(stop)
+	writers.Wait()
+}
+
+func TestSetNewFrom(t *testing.T) {
+	s := concurrent.NewSetFrom("one", "two", "two")
+	if s.Len() != 2 || !s.ContainsAll("one", "two") {
+		t.Errorf("want [one two], got %v", s.Values())
+	}
+
+	seq := concurrent.NewSetFromSeq(slices.Values([]int{1, 2, 3, 3}))
+	if seq.Len() != 3 {
+		t.Errorf("want 3 items, got %d", seq.Len())
+	}
+}
+
+func TestSetBulk(t *testing.T) {
+	s := concurrent.NewSet[int]()
+	s.AddAll(1, 2, 3, 4)
+	s.AddSeq(slices.Values([]int{5, 6}))
+	if s.Len() != 6 {
+		t.Errorf("want 6 items, got %d", s.Len())
+	}
+
+	s.RemoveAll(1, 2)
+	s.RemoveSeq(slices.Values([]int{3}))
+	if s.Len() != 3 || s.ContainsAny(1, 2, 3) {
+		t.Errorf("want [4 5 6], got %v", s.Values())
+	}
+
+	if !s.ContainsAll(4, 5) || s.ContainsAll(4, 7) || !s.ContainsAll() {
+		t.Error("unexpected ContainsAll result")
+	}
+	if !s.ContainsAny(7, 6) || s.ContainsAny() {
+		t.Error("unexpected ContainsAny result")
+	}
+
+	// seq may read from the set itself
+	s.AddSeq(func(yield func(int) bool) {
+		for _, v := range s.Values() {
+			if !yield(v * 10) {
+				return
+			}
+		}
+	})
+	if !s.ContainsAll(40, 50, 60) {
+		t.Errorf("want [4 5 6 40 50 60], got %v", s.Values())
+	}
+}
+
+func TestSetRemoveIf(t *testing.T) {
+	s := concurrent.NewSetFrom(1, 2, 3, 4, 5, 6)
+	removed := s.RemoveIf(func(v int) bool { return v%2 == 0 })
+	if removed != 3 {
+		t.Errorf("want 3 removed, got %d", removed)
+	}
+	if !s.Equal(concurrent.NewSetFrom(1, 3, 5)) {
+		t.Errorf("want [1 3 5], got %v", s.Values())
+	}
+}
+
+func TestSetInPlace(t *testing.T) {
+	a := concurrent.NewSetFrom(1, 2, 3)
+	a.UnionWith(concurrent.NewSetFrom(3, 4))
+	if !a.Equal(concurrent.NewSetFrom(1, 2, 3, 4)) {
+		t.Errorf("want [1 2 3 4], got %v", a.Values())
+	}
+
+	a.IntersectWith(concurrent.NewSetFrom(2, 3, 4, 5))
+	if !a.Equal(concurrent.NewSetFrom(2, 3, 4)) {
+		t.Errorf("want [2 3 4], got %v", a.Values())
+	}
+
+	a.SubtractWith(concurrent.NewSetFrom(4))
+	if !a.Equal(concurrent.NewSetFrom(2, 3)) {
+		t.Errorf("want [2 3], got %v", a.Values())
+	}
+
+	// operating on itself must not deadlock
+	a.UnionWith(a)
+	a.IntersectWith(a)
+	if a.Len() != 2 {
+		t.Errorf("want 2 items, got %d", a.Len())
+	}
+	a.SubtractWith(a)
+	if a.Len() != 0 {
+		t.Errorf("want 0 items, got %d", a.Len())
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	s := concurrent.NewSetFrom(1, 2, 3)
+
+	// the loop body may write to the set without deadlocking
+	for v := range s.All() {
+		s.Remove(v)
+		s.Add(v * 10)
+	}
+	if !s.Equal(concurrent.NewSetFrom(10, 20, 30)) {
+		t.Errorf("want [10 20 30], got %v", s.Values())
+	}
+
+	sum := 0
+	for v := range s.AllLocked() {
+		sum += v
+	}
+	if sum != 60 {
+		t.Errorf("want 60, got %d", sum)
+	}
+}
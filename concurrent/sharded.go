@@ -0,0 +1,171 @@
+package concurrent
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+// ShardedMap is a concurrent map split into independently locked shards.
+// Keys are spread across the shards by a hash function, so writers to
+// different shards do not contend for the same lock.
+type ShardedMap[K comparable, V any] struct {
+	shards []*Map[K, V]
+	mask   uint64
+	hash   func(K) uint64
+}
+
+// NewShardedMap creates a sharded map with at least the given number of shards,
+// hashing keys with hash/maphash. The number of shards is rounded up to a power of two.
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	seed := maphash.MakeSeed()
+	return NewShardedMapFunc[K, V](shards, func(key K) uint64 {
+		return maphash.Comparable(seed, key)
+	})
+}
+
+// NewShardedMapFunc creates a sharded map with at least the given number of shards,
+// using hash to pick the shard of each key. The number of shards is rounded up
+// to a power of two.
+func NewShardedMapFunc[K comparable, V any](shards int, hash func(K) uint64) *ShardedMap[K, V] {
+	n := 1
+	if shards > 1 {
+		n = 1 << bits.Len(uint(shards-1))
+	}
+
+	m := &ShardedMap[K, V]{
+		shards: make([]*Map[K, V], n),
+		mask:   uint64(n - 1),
+		hash:   hash,
+	}
+	for i := range m.shards {
+		m.shards[i] = NewMap[K, V]()
+	}
+	return m
+}
+
+// shard returns the shard that holds key.
+func (m *ShardedMap[K, V]) shard(key K) *Map[K, V] {
+	return m.shards[m.hash(key)&m.mask]
+}
+
+// Shards returns the number of shards.
+func (m *ShardedMap[K, V]) Shards() int {
+	return len(m.shards)
+}
+
+// Get returns the value associated with the given key.
+// If the key is not found, Get returns the zero value for the value type and false.
+func (m *ShardedMap[K, V]) Get(key K) (value V, ok bool) {
+	return m.shard(key).Get(key)
+}
+
+// Set sets the given value to the given key in the map.
+func (m *ShardedMap[K, V]) Set(key K, value V) {
+	m.shard(key).Set(key, value)
+}
+
+// Delete deletes the item with the given key from the map.
+func (m *ShardedMap[K, V]) Delete(key K) {
+	m.shard(key).Delete(key)
+}
+
+// Len returns the number of items in the map.
+// Shards are counted one at a time, so concurrent writes may or may not be included.
+func (m *ShardedMap[K, V]) Len() int {
+	length := 0
+	for _, s := range m.shards {
+		length += s.Len()
+	}
+	return length
+}
+
+// Range calls f sequentially for each key and value present in the map.
+// If f returns false, range stops the iteration.
+// Each shard is read-locked only while it is being iterated.
+func (m *ShardedMap[K, V]) Range(f func(key K, value V) bool) {
+	for _, s := range m.shards {
+		stopped := false
+		s.Range(func(key K, value V) bool {
+			if !f(key, value) {
+				stopped = true
+				return false
+			}
+			return true
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the key-value pairs in the map.
+// Each shard is snapshotted just before it is iterated and no lock is held
+// while yielding, so the loop body may freely read and write the map.
+func (m *ShardedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range m.shards {
+			for k, v := range s.All() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns all keys in the map.
+func (m *ShardedMap[K, V]) Keys() []K {
+	var keys []K
+	for _, s := range m.shards {
+		keys = append(keys, s.Keys()...)
+	}
+	return keys
+}
+
+// Values returns all values in the map.
+func (m *ShardedMap[K, V]) Values() []V {
+	var values []V
+	for _, s := range m.shards {
+		values = append(values, s.Values()...)
+	}
+	return values
+}
+
+// Clear removes all items from the map.
+func (m *ShardedMap[K, V]) Clear() {
+	for _, s := range m.shards {
+		s.Clear()
+	}
+}
+
+// GetOrSet returns the existing value for the key if present.
+// Otherwise, it sets the given value and returns it.
+func (m *ShardedMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	return m.shard(key).GetOrSet(key, value)
+}
+
+// GetOrCompute is like Map.GetOrCompute. Calls are coalesced per key within its shard.
+func (m *ShardedMap[K, V]) GetOrCompute(key K, fn func() (V, error)) (V, error) {
+	return m.shard(key).GetOrCompute(key, fn)
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any.
+func (m *ShardedMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	return m.shard(key).LoadAndDelete(key)
+}
+
+// Compute is like Map.Compute. fn runs under the lock of the key's shard.
+func (m *ShardedMap[K, V]) Compute(key K, fn func(old V, ok bool) (value V, keep bool)) (V, bool) {
+	return m.shard(key).Compute(key, fn)
+}
+
+// Update is like Map.Update. fn runs under the lock of the key's shard.
+func (m *ShardedMap[K, V]) Update(key K, fn func(old V) V) (V, bool) {
+	return m.shard(key).Update(key, fn)
+}
+
+// Upsert is like Map.Upsert. fn runs under the lock of the key's shard.
+func (m *ShardedMap[K, V]) Upsert(key K, fn func(old V, ok bool) V) V {
+	return m.shard(key).Upsert(key, fn)
+}
@@ -0,0 +1,160 @@
+package concurrent_test
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestShardedMap(t *testing.T) {
+	m := concurrent.NewShardedMap[string, int](5)
+	if m.Shards() != 8 {
+		t.Errorf("want 8 shards, got %d", m.Shards())
+	}
+
+	for i := range 100 {
+		m.Set(strconv.Itoa(i), i)
+	}
+	if m.Len() != 100 {
+		t.Errorf("want 100 items, got %d", m.Len())
+	}
+
+	v, ok := m.Get("42")
+	if !ok || v != 42 {
+		t.Errorf("want 42 true, got %d %v", v, ok)
+	}
+
+	m.Delete("42")
+	if _, ok := m.Get("42"); ok {
+		t.Error("want key to be not found, got found")
+	}
+
+	keys := m.Keys()
+	values := m.Values()
+	if len(keys) != 99 || len(values) != 99 {
+		t.Errorf("want 99 keys and values, got %d and %d", len(keys), len(values))
+	}
+	slices.Sort(values)
+	if values[0] != 0 || values[98] != 99 {
+		t.Errorf("want values 0..99 without 42, got %v", values)
+	}
+
+	var visited int
+	m.Range(func(k string, v int) bool {
+		visited++
+		return visited < 10
+	})
+	if visited != 10 {
+		t.Errorf("want Range to stop after 10 items, got %d", visited)
+	}
+
+	m.Clear()
+	if m.Len() != 0 {
+		t.Errorf("want 0 items, got %d", m.Len())
+	}
+}
+
+func TestShardedMapFunc(t *testing.T) {
+	// All keys in one shard still behave like a plain map.
+	m := concurrent.NewShardedMapFunc[int, int](4, func(int) uint64 { return 0 })
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Set(i, i*i)
+		}()
+	}
+	wg.Wait()
+
+	if m.Len() != 100 {
+		t.Errorf("want 100 items, got %d", m.Len())
+	}
+	if v, _ := m.Get(9); v != 81 {
+		t.Errorf("want 81, got %d", v)
+	}
+}
+
+const benchKeys = 1 << 12
+
+func benchKeySet() []string {
+	keys := make([]string, benchKeys)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+// benchmarkWriteHeavy runs a 3:1 write/read mix from 8×GOMAXPROCS goroutines.
+func benchmarkWriteHeavy(b *testing.B, set func(string, int), get func(string)) {
+	keys := benchKeySet()
+	b.SetParallelism(8)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i&(benchKeys-1)]
+			if i%4 == 0 {
+				get(key)
+			} else {
+				set(key, i)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMapWriteHeavy(b *testing.B) {
+	m := concurrent.NewMap[string, int]()
+	benchmarkWriteHeavy(b, m.Set, func(k string) { m.Get(k) })
+}
+
+func BenchmarkShardedMapWriteHeavy(b *testing.B) {
+	m := concurrent.NewShardedMap[string, int](32)
+	benchmarkWriteHeavy(b, m.Set, func(k string) { m.Get(k) })
+}
+
+func BenchmarkSyncMapWriteHeavy(b *testing.B) {
+	var m sync.Map
+	benchmarkWriteHeavy(b, func(k string, v int) { m.Store(k, v) }, func(k string) { m.Load(k) })
+}
+
+func TestShardedMapCompute(t *testing.T) {
+	m := concurrent.NewShardedMap[string, int](4)
+
+	if v, loaded := m.GetOrSet("a", 1); loaded || v != 1 {
+		t.Errorf("want 1 false, got %d %v", v, loaded)
+	}
+	if v, ok := m.Update("a", func(old int) int { return old * 10 }); !ok || v != 10 {
+		t.Errorf("want 10 true, got %d %v", v, ok)
+	}
+	if v := m.Upsert("b", func(old int, ok bool) int { return old + 2 }); v != 2 {
+		t.Errorf("want 2, got %d", v)
+	}
+	if _, ok := m.Compute("b", func(old int, ok bool) (int, bool) { return 0, false }); ok {
+		t.Error("want key to be deleted")
+	}
+	if v, loaded := m.LoadAndDelete("a"); !loaded || v != 10 || m.Len() != 0 {
+		t.Errorf("want 10 true and an empty map, got %d %v %d", v, loaded, m.Len())
+	}
+}
+
+func TestShardedMapAll(t *testing.T) {
+	m := concurrent.NewShardedMap[int, int](4)
+	for i := range 10 {
+		m.Set(i, i)
+	}
+
+	sum := 0
+	for k, v := range m.All() {
+		sum += v
+		m.Delete(k)
+	}
+	if sum != 45 || m.Len() != 0 {
+		t.Errorf("want 45 and an empty map, got %d and %d items", sum, m.Len())
+	}
+}
@@ -0,0 +1,108 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+)
+
+// GroupResult holds the outcome of a call made through a Group.
+type GroupResult[V any] struct {
+	Value  V     // Value returned by the call
+	Err    error // Error returned by the call
+	Shared bool  // Whether the outcome was delivered to more than one caller
+}
+
+// call is an in-flight or completed Group call.
+type call[V any] struct {
+	done  chan struct{} // Closed once value and err are set
+	value V
+	err   error
+	dups  int
+	chans []chan<- GroupResult[V]
+}
+
+// Group deduplicates concurrent calls that share a key: while a call for a
+// key is in flight, later callers for the same key wait for it and receive
+// its result instead of starting their own.
+//
+// The zero value is ready to use. A Group must not be copied after first use.
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// Do runs fn and returns its results, unless a call for key is already in
+// flight, in which case it waits for that call and returns its results.
+// shared reports whether the results were given to more than one caller.
+//
+// If fn panics, every caller receives a *PanicError.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (value V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		<-c.done
+		return c.value, c.err, true
+	}
+
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	g.run(key, c, fn)
+	return c.value, c.err, c.dups > 0
+}
+
+// DoChan is like Do but returns a channel that receives the result once it
+// is ready. The channel is buffered, so it need not be read.
+func (g *Group[K, V]) DoChan(key K, fn func() (V, error)) <-chan GroupResult[V] {
+	ch := make(chan GroupResult[V], 1)
+
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.mu.Unlock()
+		return ch
+	}
+
+	c := &call[V]{done: make(chan struct{}), chans: []chan<- GroupResult[V]{ch}}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	go g.run(key, c, fn)
+	return ch
+}
+
+// run runs fn for c and delivers its outcome to every waiter.
+func (g *Group[K, V]) run(key K, c *call[V], fn func() (V, error)) {
+	c.value, c.err = runTask(context.Background(), job[V]{
+		task: func(context.Context) (V, error) { return fn() },
+	})
+
+	g.mu.Lock()
+	// The call may have been forgotten and replaced by a newer one.
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	close(c.done)
+	for _, ch := range c.chans {
+		ch <- GroupResult[V]{Value: c.value, Err: c.err, Shared: c.dups > 0}
+	}
+	g.mu.Unlock()
+}
+
+// Forget makes the Group forget the in-flight call for key, if any.
+// Later calls for key start fn again instead of waiting for it.
+// Callers already waiting still receive its result.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
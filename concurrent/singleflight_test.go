@@ -0,0 +1,157 @@
+package concurrent_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestGroupDo(t *testing.T) {
+	var g concurrent.Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const n = 10
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, s := g.Do("key", func() (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if v != 42 || err != nil {
+				t.Errorf("want 42 <nil>, got %d %v", v, err)
+			}
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+
+	// Give the callers time to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("want fn to run once, ran %d times", calls.Load())
+	}
+	if shared.Load() != n {
+		t.Errorf("want all %d results shared, got %d", n, shared.Load())
+	}
+
+	// The call is finished, so the next one runs again.
+	v, _, s := g.Do("key", func() (int, error) { return 7, nil })
+	if v != 7 || s {
+		t.Errorf("want 7 false, got %d %v", v, s)
+	}
+}
+
+func TestGroupDoErrorAndPanic(t *testing.T) {
+	var g concurrent.Group[int, string]
+
+	boom := errors.New("boom")
+	if _, err, _ := g.Do(1, func() (string, error) { return "", boom }); err != boom {
+		t.Errorf("want %v, got %v", boom, err)
+	}
+
+	_, err, _ := g.Do(1, func() (string, error) { panic("oops") })
+	var panicErr *concurrent.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "oops" {
+		t.Errorf("want PanicError with value oops, got %v", err)
+	}
+}
+
+func TestGroupDoChan(t *testing.T) {
+	var g concurrent.Group[string, int]
+	release := make(chan struct{})
+
+	first := g.DoChan("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	second := g.DoChan("key", func() (int, error) { return 2, nil })
+	close(release)
+
+	for _, ch := range []<-chan concurrent.GroupResult[int]{first, second} {
+		r := <-ch
+		if r.Value != 1 || r.Err != nil || !r.Shared {
+			t.Errorf("want {1 <nil> true}, got %+v", r)
+		}
+	}
+}
+
+func TestGroupForget(t *testing.T) {
+	var g concurrent.Group[string, int]
+	release := make(chan struct{})
+
+	first := g.DoChan("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	g.Forget("key")
+
+	v, _, shared := g.Do("key", func() (int, error) { return 2, nil })
+	if v != 2 || shared {
+		t.Errorf("want 2 false, got %d %v", v, shared)
+	}
+
+	close(release)
+	if r := <-first; r.Value != 1 {
+		t.Errorf("want 1, got %d", r.Value)
+	}
+}
+
+func TestMapGetOrCompute(t *testing.T) {
+	m := concurrent.NewMap[string, int]()
+	var calls atomic.Int32
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := m.GetOrCompute("key", func() (int, error) {
+				calls.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return 42, nil
+			})
+			if v != 42 || err != nil {
+				t.Errorf("want 42 <nil>, got %d %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("want fn to run once, ran %d times", calls.Load())
+	}
+	if v, ok := m.Get("key"); !ok || v != 42 {
+		t.Errorf("want 42 true, got %d %v", v, ok)
+	}
+}
+
+func TestMapGetOrComputeError(t *testing.T) {
+	m := concurrent.NewShardedMap[string, int](4)
+
+	boom := errors.New("boom")
+	if _, err := m.GetOrCompute("key", func() (int, error) { return 0, boom }); err != boom {
+		t.Errorf("want %v, got %v", boom, err)
+	}
+	if _, ok := m.Get("key"); ok {
+		t.Error("want failed computation not to be stored")
+	}
+
+	v, err := m.GetOrCompute("key", func() (int, error) { return 1, nil })
+	if v != 1 || err != nil {
+		t.Errorf("want 1 <nil>, got %d %v", v, err)
+	}
+}
@@ -0,0 +1,124 @@
+package concurrent
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/abiiranathan/fn"
+)
+
+// chunkTasks splits s into at most maxWorkers contiguous chunks and returns one
+// task per chunk. Each task calls each with the chunk index, the index in s and
+// the value of every element of its chunk, stopping early if its context is done.
+// A panic in each is returned as a *PanicError whose Index is the element's.
+func chunkTasks[T any](s []T, maxWorkers int, each func(chunk, i int, v T)) ([]func(context.Context) error, int) {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.GOMAXPROCS(0)
+	}
+	chunkSize := (len(s) + maxWorkers - 1) / maxWorkers
+	if chunkSize == 0 {
+		return nil, maxWorkers
+	}
+
+	chunks := fn.Chunk(s, chunkSize)
+	tasks := make([]func(context.Context) error, len(chunks))
+	for c, chunk := range chunks {
+		offset := c * chunkSize
+		tasks[c] = func(ctx context.Context) (err error) {
+			i := offset
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Index: i, Stack: debug.Stack()}
+				}
+			}()
+
+			for ; i < offset+len(chunk); i++ {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				each(c, i, s[i])
+			}
+			return nil
+		}
+	}
+	return tasks, min(maxWorkers, len(chunks))
+}
+
+// ParallelMap is like fn.Map but applies f to the elements of s on up to
+// maxWorkers goroutines. The order of the results matches the order of s.
+// If maxWorkers is not positive, GOMAXPROCS workers are used.
+// It returns nil and the context error if ctx is done before all elements are mapped.
+// If f panics, the error is a *PanicError whose Index is the element's index in s.
+func ParallelMap[T, U any](ctx context.Context, s []T, f func(T) U, maxWorkers int) ([]U, error) {
+	result := make([]U, len(s))
+	tasks, workers := chunkTasks(s, maxWorkers, func(_, i int, v T) {
+		result[i] = f(v)
+	})
+
+	if err := ParallelContext(ctx, tasks, workers, StopOnError(), Wait()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParallelFilter is like fn.Filter but evaluates f on up to maxWorkers goroutines.
+// The kept elements are returned in the order they appear in s.
+// If maxWorkers is not positive, GOMAXPROCS workers are used.
+// If f panics, the error is a *PanicError whose Index is the element's index in s.
+func ParallelFilter[T any](ctx context.Context, s []T, f func(T) bool, maxWorkers int) ([]T, error) {
+	var kept [][]T
+	tasks, workers := chunkTasks(s, maxWorkers, func(chunk, _ int, v T) {
+		if f(v) {
+			kept[chunk] = append(kept[chunk], v)
+		}
+	})
+	kept = make([][]T, len(tasks))
+
+	if err := ParallelContext(ctx, tasks, workers, StopOnError(), Wait()); err != nil {
+		return nil, err
+	}
+	return fn.Flatten(kept), nil
+}
+
+// ParallelForEach is like fn.ForEach but calls f on up to maxWorkers goroutines.
+// Calls for different elements may run concurrently and in any order.
+// It returns once every call that was started has returned.
+// If maxWorkers is not positive, GOMAXPROCS workers are used.
+// If f panics, the error is a *PanicError whose Index is the element's index in s.
+func ParallelForEach[T any](ctx context.Context, s []T, f func(T), maxWorkers int) error {
+	tasks, workers := chunkTasks(s, maxWorkers, func(_, _ int, v T) {
+		f(v)
+	})
+	return ParallelContext(ctx, tasks, workers, StopOnError(), Wait())
+}
+
+// ParallelReduce is like fn.Reduce but splits s into chunks that are reduced
+// concurrently, each starting from initial, and then combines the partial
+// results in order with merge.
+//
+// For the result to match fn.Reduce, initial must be an identity for merge
+// and merge must be associative, e.g. (0, +) or (1, *).
+// If maxWorkers is not positive, GOMAXPROCS workers are used.
+// If f panics, the error is a *PanicError whose Index is the element's index in s.
+func ParallelReduce[T, U any](ctx context.Context, s []T, f func(U, T) U, merge func(U, U) U, initial U, maxWorkers int) (U, error) {
+	var partials []U
+	tasks, workers := chunkTasks(s, maxWorkers, func(chunk, _ int, v T) {
+		partials[chunk] = f(partials[chunk], v)
+	})
+	partials = make([]U, len(tasks))
+	for i := range partials {
+		partials[i] = initial
+	}
+
+	if err := ParallelContext(ctx, tasks, workers, StopOnError(), Wait()); err != nil {
+		var zero U
+		return zero, err
+	}
+
+	result := initial
+	for _, p := range partials {
+		result = merge(result, p)
+	}
+	return result, nil
+}
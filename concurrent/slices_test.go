@@ -0,0 +1,135 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abiiranathan/fn"
+	"github.com/abiiranathan/fn/concurrent"
+)
+
+func TestParallelMap(t *testing.T) {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	square := func(v int) int { return v * v }
+
+	got, err := concurrent.ParallelMap(context.Background(), s, square, 8)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+
+	want := fn.Map(s, square)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want results in input order")
+	}
+
+	got, err = concurrent.ParallelMap(context.Background(), []int{}, square, 0)
+	if err != nil || len(got) != 0 {
+		t.Errorf("want empty result, got %v %v", got, err)
+	}
+}
+
+func TestParallelMapCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := concurrent.ParallelMap(ctx, []int{1, 2, 3}, func(v int) int { return v }, 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("want nil, got %v", got)
+	}
+}
+
+func TestParallelMapPanicIndex(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5}
+	_, err := concurrent.ParallelMap(context.Background(), s, func(v int) int {
+		if v == 4 {
+			panic("four")
+		}
+		return v
+	}, 2)
+
+	var pe *concurrent.PanicError
+	if !errors.As(err, &pe) || pe.Index != 4 {
+		t.Errorf("want a *PanicError for element 4, got %v", err)
+	}
+}
+
+func TestParallelFilter(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	got, err := concurrent.ParallelFilter(context.Background(), s, isEven, 3)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+
+	want := []int{2, 4, 6, 8, 10}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestParallelForEach(t *testing.T) {
+	var sum atomic.Int64
+	err := concurrent.ParallelForEach(context.Background(), []int{1, 2, 3, 4, 5}, func(v int) {
+		sum.Add(int64(v))
+	}, 2)
+
+	if err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+	if sum.Load() != 15 {
+		t.Errorf("want 15, got %d", sum.Load())
+	}
+}
+
+func TestParallelForEachWaits(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var running atomic.Int32
+	err := concurrent.ParallelForEach(ctx, []int{1, 2, 3, 4}, func(v int) {
+		running.Add(1)
+		if v == 1 {
+			cancel()
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+	}, 4)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+	if n := running.Load(); n != 0 {
+		t.Errorf("want no calls running after return, got %d", n)
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	s := []string{"a", "b", "c", "d", "e", "f", "g"}
+	concat := func(acc, v string) string { return acc + v }
+
+	got, err := concurrent.ParallelReduce(context.Background(), s, concat, concat, "", 3)
+	if err != nil {
+		t.Fatalf("want nil error, got %v", err)
+	}
+	if want := fn.Reduce(s, concat, ""); want != got {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	count := func(acc int, v string) int { return acc + len(v) }
+	sum := func(a, b int) int { return a + b }
+	n, err := concurrent.ParallelReduce(context.Background(), s, count, sum, 0, 4)
+	if err != nil || n != 7 {
+		t.Errorf("want 7 <nil>, got %d %v", n, err)
+	}
+}
@@ -0,0 +1,139 @@
+package fn
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IndexError records the index of the element whose callback failed.
+type IndexError struct {
+	Index int   // Position of the element in the input slice
+	Err   error // Error returned by the callback
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d: %v", e.Index, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// MapErr is like Map but fn may fail. It stops at the first error and returns
+// the results for the elements before it along with the error.
+func MapErr[T, U any](s []T, fn func(T) (U, error)) ([]U, error) {
+	p := make([]U, 0, len(s))
+	for _, v := range s {
+		u, err := fn(v)
+		if err != nil {
+			return p, err
+		}
+		p = append(p, u)
+	}
+	return p, nil
+}
+
+// MapErrAll is like MapErr but does not stop at the first error.
+// Results of failed elements are left out, and every failure is returned
+// as an *IndexError joined with errors.Join.
+func MapErrAll[T, U any](s []T, fn func(T) (U, error)) ([]U, error) {
+	var errs []error
+	p := make([]U, 0, len(s))
+	for i, v := range s {
+		u, err := fn(v)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		p = append(p, u)
+	}
+	return p, errors.Join(errs...)
+}
+
+// FilterErr is like Filter but fn may fail. It stops at the first error and
+// returns the elements kept so far along with the error.
+func FilterErr[T any](s []T, fn func(T) (bool, error)) ([]T, error) {
+	p := make([]T, 0, len(s))
+	for _, v := range s {
+		ok, err := fn(v)
+		if err != nil {
+			return p, err
+		}
+		if ok {
+			p = append(p, v)
+		}
+	}
+	return p, nil
+}
+
+// FilterErrAll is like FilterErr but does not stop at the first error.
+// Failed elements are left out, and every failure is returned
+// as an *IndexError joined with errors.Join.
+func FilterErrAll[T any](s []T, fn func(T) (bool, error)) ([]T, error) {
+	var errs []error
+	p := make([]T, 0, len(s))
+	for i, v := range s {
+		ok, err := fn(v)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		if ok {
+			p = append(p, v)
+		}
+	}
+	return p, errors.Join(errs...)
+}
+
+// ReduceErr is like Reduce but fn may fail. It stops at the first error and
+// returns the value accumulated so far along with the error.
+func ReduceErr[T, U any](s []T, fn func(U, T) (U, error), initial U) (U, error) {
+	p := initial
+	for _, v := range s {
+		next, err := fn(p, v)
+		if err != nil {
+			return p, err
+		}
+		p = next
+	}
+	return p, nil
+}
+
+// ReduceErrAll is like ReduceErr but does not stop at the first error.
+// Failed elements do not contribute to the accumulated value, and every
+// failure is returned as an *IndexError joined with errors.Join.
+func ReduceErrAll[T, U any](s []T, fn func(U, T) (U, error), initial U) (U, error) {
+	var errs []error
+	p := initial
+	for i, v := range s {
+		next, err := fn(p, v)
+		if err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+			continue
+		}
+		p = next
+	}
+	return p, errors.Join(errs...)
+}
+
+// ForEachErr applies fn to each element of s, stopping at the first error.
+func ForEachErr[T any](s []T, fn func(T) error) error {
+	for _, v := range s {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachErrAll applies fn to each element of s and returns every failure
+// as an *IndexError joined with errors.Join.
+func ForEachErrAll[T any](s []T, fn func(T) error) error {
+	var errs []error
+	for i, v := range s {
+		if err := fn(v); err != nil {
+			errs = append(errs, &IndexError{Index: i, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
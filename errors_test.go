@@ -0,0 +1,126 @@
+package fn_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/abiiranathan/fn"
+)
+
+func TestMapErr(t *testing.T) {
+	got, err := fn.MapErr([]string{"1", "2", "x", "4"}, strconv.Atoi)
+	if err == nil {
+		t.Fatal("want an error, got nil")
+	}
+
+	want := []int{1, 2}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	got, err = fn.MapErr([]string{"1", "2"}, strconv.Atoi)
+	if err != nil || !reflect.DeepEqual([]int{1, 2}, got) {
+		t.Errorf("want [1 2] <nil>, got %v %v", got, err)
+	}
+}
+
+func TestMapErrAll(t *testing.T) {
+	got, err := fn.MapErrAll([]string{"1", "x", "3", "y"}, strconv.Atoi)
+	want := []int{1, 3}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("want a *strconv.NumError in %v", err)
+	}
+
+	var indices []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var ie *fn.IndexError
+		if errors.As(e, &ie) {
+			indices = append(indices, ie.Index)
+		}
+	}
+	if !reflect.DeepEqual([]int{1, 3}, indices) {
+		t.Errorf("want failures at %v, got %v", []int{1, 3}, indices)
+	}
+}
+
+func TestFilterErr(t *testing.T) {
+	errOdd := errors.New("odd")
+	f := func(v int) (bool, error) {
+		if v == 3 {
+			return false, errOdd
+		}
+		return v%2 == 0, nil
+	}
+
+	got, err := fn.FilterErr([]int{1, 2, 3, 4}, f)
+	if !errors.Is(err, errOdd) {
+		t.Errorf("want %v, got %v", errOdd, err)
+	}
+	if !reflect.DeepEqual([]int{2}, got) {
+		t.Errorf("want %v, got %v", []int{2}, got)
+	}
+
+	got, err = fn.FilterErrAll([]int{1, 2, 3, 4}, f)
+	if !errors.Is(err, errOdd) || err.Error() != "index 2: odd" {
+		t.Errorf("want %q, got %v", "index 2: odd", err)
+	}
+	if !reflect.DeepEqual([]int{2, 4}, got) {
+		t.Errorf("want %v, got %v", []int{2, 4}, got)
+	}
+}
+
+func TestReduceErr(t *testing.T) {
+	sum := func(acc int, s string) (int, error) {
+		v, err := strconv.Atoi(s)
+		return acc + v, err
+	}
+
+	got, err := fn.ReduceErr([]string{"1", "2", "x", "4"}, sum, 0)
+	if err == nil || got != 3 {
+		t.Errorf("want 3 and an error, got %v %v", got, err)
+	}
+
+	got, err = fn.ReduceErrAll([]string{"1", "2", "x", "4"}, sum, 0)
+	if err == nil || got != 7 {
+		t.Errorf("want 7 and an error, got %v %v", got, err)
+	}
+}
+
+func TestForEachErr(t *testing.T) {
+	var seen []int
+	errStop := errors.New("stop")
+	f := func(v int) error {
+		seen = append(seen, v)
+		if v%2 == 0 {
+			return errStop
+		}
+		return nil
+	}
+
+	if err := fn.ForEachErr([]int{1, 2, 3, 4}, f); !errors.Is(err, errStop) {
+		t.Errorf("want %v, got %v", errStop, err)
+	}
+	if !reflect.DeepEqual([]int{1, 2}, seen) {
+		t.Errorf("want %v, got %v", []int{1, 2}, seen)
+	}
+
+	seen = nil
+	err := fn.ForEachErrAll([]int{1, 2, 3, 4}, f)
+	if want := "index 1: stop\nindex 3: stop"; err == nil || err.Error() != want {
+		t.Errorf("want %q, got %v", want, err)
+	}
+	if len(seen) != 4 {
+		t.Errorf("want 4 calls, got %d", len(seen))
+	}
+
+	if err := fn.ForEachErrAll([]int{1, 3}, f); err != nil {
+		t.Errorf("want nil, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package fn
+
+// Option holds either a value (Some) or nothing (None).
+// The zero value is None.
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some returns an Option holding v.
+func Some[T any](v T) Option[T] {
+	return Option[T]{value: v, ok: true}
+}
+
+// None returns an empty Option.
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// IsSome reports whether o holds a value.
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone reports whether o is empty.
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// Get returns the value held by o and whether there was one.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// Unwrap returns the value held by o. It panics if o is None.
+func (o Option[T]) Unwrap() T {
+	if !o.ok {
+		panic("called Unwrap on a None Option")
+	}
+	return o.value
+}
+
+// OrElse returns the value held by o, or def if o is None.
+func (o Option[T]) OrElse(def T) T {
+	if o.ok {
+		return o.value
+	}
+	return def
+}
+
+// Map applies fn to the value held by o, if any.
+// Use MapOption to change the value type.
+func (o Option[T]) Map(fn func(T) T) Option[T] {
+	return MapOption(o, fn)
+}
+
+// FlatMap applies fn to the value held by o, if any, and returns its result.
+// Use FlatMapOption to change the value type.
+func (o Option[T]) FlatMap(fn func(T) Option[T]) Option[T] {
+	return FlatMapOption(o, fn)
+}
+
+// MapOption applies fn to the value held by o, if any.
+func MapOption[T, U any](o Option[T], fn func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return Some(fn(o.value))
+}
+
+// FlatMapOption applies fn to the value held by o, if any, and returns its result.
+func FlatMapOption[T, U any](o Option[T], fn func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+	return fn(o.value)
+}
+
+// Find returns the first element of s that satisfies the predicate fn,
+// or None if there is no such element.
+func Find[T any](s []T, fn func(T) bool) Option[T] {
+	for _, v := range s {
+		if fn(v) {
+			return Some(v)
+		}
+	}
+	return None[T]()
+}
+
+// FilterMap applies fn to each element of s and returns a new slice
+// containing the values of the results that are Some.
+func FilterMap[T, U any](s []T, fn func(T) Option[U]) []U {
+	p := make([]U, 0, len(s))
+	for _, v := range s {
+		if u, ok := fn(v).Get(); ok {
+			p = append(p, u)
+		}
+	}
+	return p
+}
@@ -0,0 +1,80 @@
+package fn_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/abiiranathan/fn"
+)
+
+func TestOption(t *testing.T) {
+	some := fn.Some(21)
+	none := fn.None[int]()
+
+	if !some.IsSome() || some.IsNone() {
+		t.Error("want Some to hold a value")
+	}
+	if none.IsSome() || !none.IsNone() {
+		t.Error("want None to be empty")
+	}
+
+	if v, ok := some.Get(); !ok || v != 21 {
+		t.Errorf("want 21 true, got %v %v", v, ok)
+	}
+	if got := none.OrElse(7); got != 7 {
+		t.Errorf("want %v, got %v", 7, got)
+	}
+
+	double := func(v int) int { return v * 2 }
+	if got := some.Map(double).Unwrap(); got != 42 {
+		t.Errorf("want %v, got %v", 42, got)
+	}
+	if got := none.Map(double); got.IsSome() {
+		t.Errorf("want None, got %v", got)
+	}
+
+	half := func(v int) fn.Option[int] {
+		if v%2 != 0 {
+			return fn.None[int]()
+		}
+		return fn.Some(v / 2)
+	}
+	if got := some.FlatMap(half); got.IsSome() {
+		t.Errorf("want None, got %v", got)
+	}
+
+	label := fn.MapOption(some, strconv.Itoa)
+	if got := label.OrElse(""); got != "21" {
+		t.Errorf("want %q, got %q", "21", got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+	none.Unwrap()
+}
+
+func TestFind(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if got := fn.Find(s, func(v int) bool { return v > 2 }); got.OrElse(-1) != 3 {
+		t.Errorf("want %v, got %v", 3, got)
+	}
+	if got := fn.Find(s, func(v int) bool { return v > 4 }); got.IsSome() {
+		t.Errorf("want None, got %v", got)
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	parse := func(s string) fn.Option[int] {
+		return fn.ResultOf(strconv.Atoi(s)).Option()
+	}
+
+	got := fn.FilterMap([]string{"1", "x", "3"}, parse)
+	want := []int{1, 3}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
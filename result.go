@@ -0,0 +1,101 @@
+package fn
+
+import "fmt"
+
+// Result holds either a value (Ok) or an error (Err).
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a successful Result holding v.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a failed Result holding err.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf builds a Result from the (value, error) pair returned by most Go functions.
+func ResultOf[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// IsOk reports whether r holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr reports whether r holds an error.
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get returns the value and error held by r.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the error held by r, or nil.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns the value held by r. It panics if r holds an error.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(fmt.Sprintf("called Unwrap on an Err Result: %v", r.err))
+	}
+	return r.value
+}
+
+// OrElse returns the value held by r, or def if r holds an error.
+func (r Result[T]) OrElse(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Option converts r to an Option, discarding the error.
+func (r Result[T]) Option() Option[T] {
+	if r.err != nil {
+		return None[T]()
+	}
+	return Some(r.value)
+}
+
+// MapResult applies fn to the value held by r. Errors are passed through unchanged.
+func MapResult[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return Ok(fn(r.value))
+}
+
+// FlatMapResult applies the fallible fn to the value held by r.
+// Errors are passed through unchanged.
+func FlatMapResult[T, U any](r Result[T], fn func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Err[U](r.err)
+	}
+	return ResultOf(fn(r.value))
+}
+
+// CollectResults returns the values held by rs. If any result holds an error,
+// it returns nil and the first error as an *IndexError.
+func CollectResults[T any](rs []Result[T]) ([]T, error) {
+	p := make([]T, len(rs))
+	for i, r := range rs {
+		if r.err != nil {
+			return nil, &IndexError{Index: i, Err: r.err}
+		}
+		p[i] = r.value
+	}
+	return p, nil
+}
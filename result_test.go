@@ -0,0 +1,68 @@
+package fn_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/abiiranathan/fn"
+)
+
+func TestResult(t *testing.T) {
+	errBad := errors.New("bad")
+	ok := fn.Ok(2)
+	bad := fn.Err[int](errBad)
+
+	if !ok.IsOk() || ok.IsErr() || ok.Err() != nil {
+		t.Error("want Ok to hold a value")
+	}
+	if bad.IsOk() || !bad.IsErr() || !errors.Is(bad.Err(), errBad) {
+		t.Error("want Err to hold an error")
+	}
+
+	if got := bad.OrElse(5); got != 5 {
+		t.Errorf("want %v, got %v", 5, got)
+	}
+
+	got := fn.MapResult(ok, strconv.Itoa)
+	if v, err := got.Get(); err != nil || v != "2" {
+		t.Errorf("want %q <nil>, got %q %v", "2", v, err)
+	}
+	if got := fn.MapResult(bad, strconv.Itoa); !errors.Is(got.Err(), errBad) {
+		t.Errorf("want %v, got %v", errBad, got.Err())
+	}
+
+	parsed := fn.FlatMapResult(fn.Ok("x"), strconv.Atoi)
+	if parsed.IsOk() {
+		t.Errorf("want an error, got %v", parsed.Unwrap())
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+	bad.Unwrap()
+}
+
+func TestCollectResults(t *testing.T) {
+	rs := fn.Map([]string{"1", "2", "3"}, func(s string) fn.Result[int] {
+		return fn.ResultOf(strconv.Atoi(s))
+	})
+
+	got, err := fn.CollectResults(rs)
+	if err != nil || !reflect.DeepEqual([]int{1, 2, 3}, got) {
+		t.Errorf("want [1 2 3] <nil>, got %v %v", got, err)
+	}
+
+	rs[1] = fn.Err[int](errors.New("bad"))
+	got, err = fn.CollectResults(rs)
+	var ie *fn.IndexError
+	if !errors.As(err, &ie) || ie.Index != 1 {
+		t.Errorf("want an *IndexError at index 1, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("want nil, got %v", got)
+	}
+}
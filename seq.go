@@ -0,0 +1,411 @@
+package fn
+
+import (
+	"iter"
+	"slices"
+)
+
+// FromSlice returns a sequence that yields the elements of s in order.
+func FromSlice[T any](s []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains seq into a new slice.
+func Collect[T any](seq iter.Seq[T]) []T {
+	return slices.Collect(seq)
+}
+
+// FilterSeq returns a sequence of the elements of seq that satisfy the predicate fn.
+func FilterSeq[T any](seq iter.Seq[T], fn func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if fn(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// MapSeq returns a sequence of the results of applying fn to each element of seq.
+func MapSeq[T, U any](seq iter.Seq[T], fn func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// ReduceSeq applies the function fn to each element of seq, accumulating
+// the result. The accumulated value is initialized to initial.
+func ReduceSeq[T, U any](seq iter.Seq[T], fn func(U, T) U, initial U) U {
+	p := initial
+	for v := range seq {
+		p = fn(p, v)
+	}
+	return p
+}
+
+// ConcatSeq returns a sequence that yields all elements of each of seqs in turn.
+func ConcatSeq[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, seq := range seqs {
+			for v := range seq {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IndexOfSeq returns the position of the first occurrence of elem in seq.
+// If elem is not in seq, IndexOfSeq returns -1.
+func IndexOfSeq[T comparable](seq iter.Seq[T], elem T) int {
+	i := 0
+	for v := range seq {
+		if v == elem {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// DistinctSeq returns a sequence that yields each unique element of seq once,
+// in order of first occurrence.
+func DistinctSeq[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for v := range seq {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// DistinctFuncSeq returns a sequence that yields the elements of seq whose key,
+// as returned by fn, has not been seen before.
+func DistinctFuncSeq[T any, U comparable](seq iter.Seq[T], fn func(T) U) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[U]struct{})
+		for v := range seq {
+			key := fn(v)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ChunkSeq returns a sequence of slices of size chunkSize.
+// The last slice may have fewer than chunkSize elements.
+// Each yielded slice is freshly allocated and may be retained by the caller.
+func ChunkSeq[T any](seq iter.Seq[T], chunkSize int) iter.Seq[[]T] {
+	if chunkSize < 1 {
+		panic("chunkSize must be greater than zero")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, chunkSize)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) == chunkSize {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, chunkSize)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// PartitionSeq returns two sequences, the first yielding the elements of seq
+// that satisfy the predicate fn, and the second yielding the rest.
+// Each returned sequence iterates seq independently.
+func PartitionSeq[T any](seq iter.Seq[T], fn func(T) bool) (yes, no iter.Seq[T]) {
+	yes = FilterSeq(seq, fn)
+	no = FilterSeq(seq, func(v T) bool { return !fn(v) })
+	return
+}
+
+// FlattenSeq returns a sequence that yields all the elements of the sub-slices in seq.
+func FlattenSeq[T any](seq iter.Seq[[]T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for sub := range seq {
+			for _, v := range sub {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ReverseSeq returns a sequence that yields the elements of seq in reverse order.
+// seq must be finite since it is buffered in full before the first element is yielded.
+func ReverseSeq[T any](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := slices.Collect(seq)
+		for i := len(buf) - 1; i >= 0; i-- {
+			if !yield(buf[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ShuffleSeq returns a sequence that yields the elements of seq in random order.
+// seq must be finite since it is buffered in full before the first element is yielded.
+func ShuffleSeq[T any](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := slices.Collect(seq)
+		Shuffle(buf)
+		for _, v := range buf {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// TakeSeq returns a sequence of the first n elements of seq.
+func TakeSeq[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			i++
+			if i == n {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhileSeq returns a sequence of the elements of seq that satisfy
+// the predicate fn, stopping at the first element that does not.
+func TakeWhileSeq[T any](seq iter.Seq[T], fn func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if !fn(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// DropSeq returns a sequence of all but the first n elements of seq.
+// This is the opposite of TakeSeq.
+func DropSeq[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for v := range seq {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// CountSeq returns the number of elements in seq that satisfy the predicate fn.
+func CountSeq[T any](seq iter.Seq[T], fn func(T) bool) int {
+	count := 0
+	for v := range seq {
+		if fn(v) {
+			count++
+		}
+	}
+	return count
+}
+
+// AllSeq returns true if all elements in seq satisfy the predicate fn.
+// It stops at the first element that does not.
+func AllSeq[T any](seq iter.Seq[T], fn func(T) bool) bool {
+	for v := range seq {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// AnySeq returns true if at least one element in seq satisfies the predicate fn.
+// It stops at the first element that does.
+func AnySeq[T any](seq iter.Seq[T], fn func(T) bool) bool {
+	for v := range seq {
+		if fn(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// ZipSeq returns a sequence of the results of applying fn to the elements of
+// s1 and s2 pairwise. s1 and s2 must have the same length; ZipSeq panics
+// once one of them is exhausted before the other.
+func ZipSeq[T, U, V any](s1 iter.Seq[T], s2 iter.Seq[U], fn func(T, U) V) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		next1, stop1 := iter.Pull(s1)
+		defer stop1()
+		next2, stop2 := iter.Pull(s2)
+		defer stop2()
+
+		for {
+			v1, ok1 := next1()
+			v2, ok2 := next2()
+			if ok1 != ok2 {
+				panic("sequences must have the same length")
+			}
+			if !ok1 || !yield(fn(v1, v2)) {
+				return
+			}
+		}
+	}
+}
+
+// ZipShortestSeq returns a sequence of the results of applying fn to the elements
+// of s1 and s2 pairwise, stopping when the shorter sequence is exhausted.
+func ZipShortestSeq[T, U, V any](s1 iter.Seq[T], s2 iter.Seq[U], fn func(T, U) V) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		next2, stop2 := iter.Pull(s2)
+		defer stop2()
+
+		for v1 := range s1 {
+			v2, ok := next2()
+			if !ok || !yield(fn(v1, v2)) {
+				return
+			}
+		}
+	}
+}
+
+// ZipWithIndexSeq returns a sequence of the results of applying fn to the
+// elements of seq and their index.
+func ZipWithIndexSeq[T, U any](seq iter.Seq[T], fn func(int, T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(fn(i, v)) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Enumerate returns a sequence of index-value pairs for the elements of seq.
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// ZipPairs returns a sequence of pairs taken from s1 and s2, stopping when the
+// shorter sequence is exhausted.
+func ZipPairs[T, U any](s1 iter.Seq[T], s2 iter.Seq[U]) iter.Seq2[T, U] {
+	return func(yield func(T, U) bool) {
+		next2, stop2 := iter.Pull(s2)
+		defer stop2()
+
+		for v1 := range s1 {
+			v2, ok := next2()
+			if !ok || !yield(v1, v2) {
+				return
+			}
+		}
+	}
+}
+
+// ForEachSeq applies the function fn to each element of seq.
+func ForEachSeq[T any](seq iter.Seq[T], fn func(T)) {
+	for v := range seq {
+		fn(v)
+	}
+}
+
+// RotateLeftSeq returns a sequence that yields the elements of seq rotated
+// to the left by positions. Only the first positions elements are buffered,
+// so the rest of seq is streamed through.
+func RotateLeftSeq[T any](seq iter.Seq[T], positions int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if positions <= 0 {
+			for v := range seq {
+				if !yield(v) {
+					return
+				}
+			}
+			return
+		}
+
+		head := make([]T, 0, positions)
+		streaming := false
+		for v := range seq {
+			if len(head) < positions {
+				head = append(head, v)
+				continue
+			}
+			streaming = true
+			if !yield(v) {
+				return
+			}
+		}
+
+		// seq was shorter than positions, rotate what we have.
+		if !streaming {
+			RotateLeft(head, positions)
+		}
+		for _, v := range head {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// RotateRightSeq returns a sequence that yields the elements of seq rotated
+// to the right by positions. seq must be finite since it is buffered in full.
+func RotateRightSeq[T any](seq iter.Seq[T], positions int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		buf := slices.Collect(seq)
+		RotateRight(buf, positions)
+		for _, v := range buf {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
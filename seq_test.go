@@ -0,0 +1,256 @@
+package fn_test
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/abiiranathan/fn"
+)
+
+// naturals yields 1, 2, 3, ... forever and records how many values it produced.
+func naturals(produced *int) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 1; ; i++ {
+			*produced = i
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromSliceCollect(t *testing.T) {
+	s := []int{1, 2, 3}
+	got := fn.Collect(fn.FromSlice(s))
+	if !reflect.DeepEqual(s, got) {
+		t.Errorf("want %v, got %v", s, got)
+	}
+}
+
+func TestFilterMapSeqShortCircuit(t *testing.T) {
+	var produced int
+	isEven := func(v int) bool { return v%2 == 0 }
+	square := func(v int) int { return v * v }
+
+	got := fn.Collect(fn.TakeSeq(fn.MapSeq(fn.FilterSeq(naturals(&produced), isEven), square), 3))
+	want := []int{4, 16, 36}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if produced != 6 {
+		t.Errorf("want source to produce 6 values, got %d", produced)
+	}
+}
+
+func TestReduceSeq(t *testing.T) {
+	s := fn.FromSlice([]int{1, 2, 3, 4, 5})
+	got := fn.ReduceSeq(s, func(acc, v int) int { return acc + v }, 0)
+	if got != 15 {
+		t.Errorf("want %v, got %v", 15, got)
+	}
+}
+
+func TestConcatSeq(t *testing.T) {
+	got := fn.Collect(fn.ConcatSeq(fn.FromSlice([]int{1, 2}), fn.FromSlice([]int{3}), fn.FromSlice([]int{4, 5})))
+	want := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestIndexOfSeq(t *testing.T) {
+	var produced int
+	if got := fn.IndexOfSeq(naturals(&produced), 4); got != 3 {
+		t.Errorf("want %v, got %v", 3, got)
+	}
+
+	if got := fn.IndexOfSeq(fn.FromSlice([]int{1, 2}), 4); got != -1 {
+		t.Errorf("want %v, got %v", -1, got)
+	}
+}
+
+func TestDistinctSeq(t *testing.T) {
+	got := fn.Collect(fn.DistinctSeq(fn.FromSlice([]int{1, 2, 2, 3, 3, 3})))
+	want := []int{1, 2, 3}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	names := fn.FromSlice([]string{"Alice", "alan", "Bob", "bill"})
+	got2 := fn.Collect(fn.DistinctFuncSeq(names, func(s string) byte { return s[0] | 0x20 }))
+	want2 := []string{"Alice", "Bob"}
+	if !reflect.DeepEqual(want2, got2) {
+		t.Errorf("want %v, got %v", want2, got2)
+	}
+}
+
+func TestChunkSeq(t *testing.T) {
+	s := fn.FromSlice([]int{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	got := fn.Collect(fn.ChunkSeq(s, 4))
+	want := [][]int{{1, 2, 3, 4}, {5, 6, 7, 8}, {9}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// round trip through FlattenSeq
+	flat := fn.Collect(fn.FlattenSeq(fn.ChunkSeq(s, 2)))
+	if !reflect.DeepEqual(fn.Collect(s), flat) {
+		t.Errorf("want %v, got %v", fn.Collect(s), flat)
+	}
+}
+
+func TestPartitionSeq(t *testing.T) {
+	isEven := func(v int) bool { return v%2 == 0 }
+	evens, odds := fn.PartitionSeq(fn.FromSlice([]int{1, 2, 3, 4, 5}), isEven)
+	if got := fn.Collect(evens); !reflect.DeepEqual([]int{2, 4}, got) {
+		t.Errorf("want %v, got %v", []int{2, 4}, got)
+	}
+	if got := fn.Collect(odds); !reflect.DeepEqual([]int{1, 3, 5}, got) {
+		t.Errorf("want %v, got %v", []int{1, 3, 5}, got)
+	}
+}
+
+func TestReverseSeq(t *testing.T) {
+	got := fn.Collect(fn.ReverseSeq(fn.FromSlice([]int{1, 2, 3})))
+	want := []int{3, 2, 1}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestShuffleSeq(t *testing.T) {
+	got := fn.Collect(fn.ShuffleSeq(fn.FromSlice([]int{1, 2, 3, 4, 5})))
+	slices.Sort(got)
+	want := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want a permutation of %v, got %v", want, got)
+	}
+}
+
+func TestTakeWhileDropSeq(t *testing.T) {
+	var produced int
+	got := fn.Collect(fn.TakeWhileSeq(naturals(&produced), func(v int) bool { return v < 4 }))
+	want := []int{1, 2, 3}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	got = fn.Collect(fn.TakeSeq(fn.DropSeq(naturals(&produced), 3), 2))
+	want = []int{4, 5}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := fn.Collect(fn.TakeSeq(naturals(&produced), 0)); len(got) != 0 {
+		t.Errorf("want empty, got %v", got)
+	}
+}
+
+func TestCountAllAnySeq(t *testing.T) {
+	s := fn.FromSlice([]int{1, 2, 3, 4, 5})
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	if got := fn.CountSeq(s, isEven); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+	if fn.AllSeq(s, isEven) {
+		t.Error("want false, got true")
+	}
+
+	var produced int
+	if !fn.AnySeq(naturals(&produced), func(v int) bool { return v > 10 }) {
+		t.Error("want true, got false")
+	}
+	if produced != 11 {
+		t.Errorf("want source to produce 11 values, got %d", produced)
+	}
+}
+
+func TestZipSeq(t *testing.T) {
+	ages := fn.FromSlice([]int{10, 20, 30})
+	names := fn.FromSlice([]string{"Abiira", "Dan", "Joseph"})
+	f := func(age int, name string) string { return fmt.Sprintf("%s:%d", name, age) }
+
+	got := fn.Collect(fn.ZipSeq(ages, names, f))
+	want := []string{"Abiira:10", "Dan:20", "Joseph:30"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+	fn.Collect(fn.ZipSeq(fn.FromSlice([]int{10, 20}), names, f))
+}
+
+func TestZipShortestSeq(t *testing.T) {
+	var produced int
+	names := fn.FromSlice([]string{"Abiira", "Dan"})
+	f := func(n int, name string) string { return fmt.Sprintf("%d%s", n, name) }
+
+	got := fn.Collect(fn.ZipShortestSeq(naturals(&produced), names, f))
+	want := []string{"1Abiira", "2Dan"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	var pairs []string
+	for n, name := range fn.ZipPairs(naturals(&produced), names) {
+		pairs = append(pairs, f(n, name))
+	}
+	if !reflect.DeepEqual(want, pairs) {
+		t.Errorf("want %v, got %v", want, pairs)
+	}
+}
+
+func TestZipWithIndexSeq(t *testing.T) {
+	s := fn.FromSlice([]string{"a", "b", "c"})
+	got := fn.Collect(fn.ZipWithIndexSeq(s, func(i int, v string) string { return fmt.Sprintf("%d%s", i, v) }))
+	want := []string{"0a", "1b", "2c"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	var idx []int
+	for i := range fn.Enumerate(s) {
+		idx = append(idx, i)
+	}
+	if !reflect.DeepEqual([]int{0, 1, 2}, idx) {
+		t.Errorf("want %v, got %v", []int{0, 1, 2}, idx)
+	}
+}
+
+func TestForEachSeq(t *testing.T) {
+	var sum int
+	fn.ForEachSeq(fn.FromSlice([]int{1, 2, 3}), func(v int) { sum += v })
+	if sum != 6 {
+		t.Errorf("want %v, got %v", 6, sum)
+	}
+}
+
+func TestRotateSeq(t *testing.T) {
+	s := fn.FromSlice([]int{1, 2, 3, 4, 5})
+
+	got := fn.Collect(fn.RotateLeftSeq(s, 2))
+	want := []int{3, 4, 5, 1, 2}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// positions greater than the length of seq
+	got = fn.Collect(fn.RotateLeftSeq(s, 7))
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	got = fn.Collect(fn.RotateRightSeq(s, 2))
+	want = []int{4, 5, 1, 2, 3}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,172 @@
+package fn
+
+import "iter"
+
+// Stream is a lazy, chainable pipeline over a sequence of values.
+// Stages are fused, so a chain like From(s).Filter(p).Take(10).Collect()
+// walks s once and stops as soon as ten matching elements have been seen.
+//
+// Go methods cannot introduce type parameters, so steps that change the
+// element type (MapStream, ChunkStream, ZipStream, ...) are free functions.
+type Stream[T any] struct {
+	seq iter.Seq[T]
+}
+
+// From returns a stream over the elements of s.
+func From[T any](s []T) Stream[T] {
+	return Stream[T]{seq: FromSlice(s)}
+}
+
+// FromSeq returns a stream over seq.
+func FromSeq[T any](seq iter.Seq[T]) Stream[T] {
+	return Stream[T]{seq: seq}
+}
+
+// Seq returns the underlying sequence of the stream.
+func (s Stream[T]) Seq() iter.Seq[T] {
+	return s.seq
+}
+
+// Filter keeps only the elements that satisfy the predicate fn.
+func (s Stream[T]) Filter(fn func(T) bool) Stream[T] {
+	return Stream[T]{seq: FilterSeq(s.seq, fn)}
+}
+
+// Map applies fn to each element. Use MapStream to change the element type.
+func (s Stream[T]) Map(fn func(T) T) Stream[T] {
+	return Stream[T]{seq: MapSeq(s.seq, fn)}
+}
+
+// Concat appends the elements of other after the elements of s.
+func (s Stream[T]) Concat(other Stream[T]) Stream[T] {
+	return Stream[T]{seq: ConcatSeq(s.seq, other.seq)}
+}
+
+// Take keeps the first n elements.
+func (s Stream[T]) Take(n int) Stream[T] {
+	return Stream[T]{seq: TakeSeq(s.seq, n)}
+}
+
+// TakeWhile keeps elements until the first one that does not satisfy fn.
+func (s Stream[T]) TakeWhile(fn func(T) bool) Stream[T] {
+	return Stream[T]{seq: TakeWhileSeq(s.seq, fn)}
+}
+
+// Drop skips the first n elements.
+func (s Stream[T]) Drop(n int) Stream[T] {
+	return Stream[T]{seq: DropSeq(s.seq, n)}
+}
+
+// Reverse reverses the order of the elements.
+// The stream is buffered in full when iterated.
+func (s Stream[T]) Reverse() Stream[T] {
+	return Stream[T]{seq: ReverseSeq(s.seq)}
+}
+
+// Shuffle randomizes the order of the elements.
+// The stream is buffered in full when iterated.
+func (s Stream[T]) Shuffle() Stream[T] {
+	return Stream[T]{seq: ShuffleSeq(s.seq)}
+}
+
+// RotateLeft rotates the elements to the left by positions.
+func (s Stream[T]) RotateLeft(positions int) Stream[T] {
+	return Stream[T]{seq: RotateLeftSeq(s.seq, positions)}
+}
+
+// RotateRight rotates the elements to the right by positions.
+// The stream is buffered in full when iterated.
+func (s Stream[T]) RotateRight(positions int) Stream[T] {
+	return Stream[T]{seq: RotateRightSeq(s.seq, positions)}
+}
+
+// Collect runs the stream and returns its elements as a slice.
+func (s Stream[T]) Collect() []T {
+	return Collect(s.seq)
+}
+
+// ForEach runs the stream, applying fn to each element.
+func (s Stream[T]) ForEach(fn func(T)) {
+	ForEachSeq(s.seq, fn)
+}
+
+// Count runs the stream and returns the number of elements that satisfy fn.
+func (s Stream[T]) Count(fn func(T) bool) int {
+	return CountSeq(s.seq, fn)
+}
+
+// All returns true if all elements satisfy fn.
+func (s Stream[T]) All(fn func(T) bool) bool {
+	return AllSeq(s.seq, fn)
+}
+
+// Any returns true if at least one element satisfies fn.
+func (s Stream[T]) Any(fn func(T) bool) bool {
+	return AnySeq(s.seq, fn)
+}
+
+// Partition runs the stream and returns the elements that satisfy fn
+// and the rest, in a single pass.
+func (s Stream[T]) Partition(fn func(T) bool) (yes, no []T) {
+	for v := range s.seq {
+		if fn(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return
+}
+
+// MapStream applies fn to each element of s, producing a stream of a new type.
+func MapStream[T, U any](s Stream[T], fn func(T) U) Stream[U] {
+	return Stream[U]{seq: MapSeq(s.seq, fn)}
+}
+
+// ReduceStream runs s, accumulating the result with fn starting from initial.
+func ReduceStream[T, U any](s Stream[T], fn func(U, T) U, initial U) U {
+	return ReduceSeq(s.seq, fn, initial)
+}
+
+// IndexOfStream returns the position of the first occurrence of elem in s, or -1.
+func IndexOfStream[T comparable](s Stream[T], elem T) int {
+	return IndexOfSeq(s.seq, elem)
+}
+
+// DistinctStream keeps only the first occurrence of each element of s.
+func DistinctStream[T comparable](s Stream[T]) Stream[T] {
+	return Stream[T]{seq: DistinctSeq(s.seq)}
+}
+
+// DistinctFuncStream keeps only the elements of s whose key, as returned by fn,
+// has not been seen before.
+func DistinctFuncStream[T any, U comparable](s Stream[T], fn func(T) U) Stream[T] {
+	return Stream[T]{seq: DistinctFuncSeq(s.seq, fn)}
+}
+
+// ChunkStream groups the elements of s into slices of size chunkSize.
+func ChunkStream[T any](s Stream[T], chunkSize int) Stream[[]T] {
+	return Stream[[]T]{seq: ChunkSeq(s.seq, chunkSize)}
+}
+
+// FlattenStream flattens a stream of slices into a stream of their elements.
+func FlattenStream[T any](s Stream[[]T]) Stream[T] {
+	return Stream[T]{seq: FlattenSeq(s.seq)}
+}
+
+// ZipStream pairs the elements of s1 and s2 with fn.
+// s1 and s2 must have the same length.
+func ZipStream[T, U, V any](s1 Stream[T], s2 Stream[U], fn func(T, U) V) Stream[V] {
+	return Stream[V]{seq: ZipSeq(s1.seq, s2.seq, fn)}
+}
+
+// ZipShortestStream pairs the elements of s1 and s2 with fn,
+// stopping at the end of the shorter stream.
+func ZipShortestStream[T, U, V any](s1 Stream[T], s2 Stream[U], fn func(T, U) V) Stream[V] {
+	return Stream[V]{seq: ZipShortestSeq(s1.seq, s2.seq, fn)}
+}
+
+// ZipWithIndexStream applies fn to each element of s and its index.
+func ZipWithIndexStream[T, U any](s Stream[T], fn func(int, T) U) Stream[U] {
+	return Stream[U]{seq: ZipWithIndexSeq(s.seq, fn)}
+}
@@ -0,0 +1,145 @@
+package fn_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/fn"
+)
+
+func TestStreamChain(t *testing.T) {
+	var calls int
+	isEven := func(v int) bool { calls++; return v%2 == 0 }
+
+	s := []int{2, 2, 4, 5, 6, 8, 8, 10, 12, 14}
+	got := fn.DistinctStream(fn.From(s).Filter(isEven)).Take(3).Collect()
+	want := []int{2, 4, 6}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// single fused pass that stops after the 5th element
+	if calls != 5 {
+		t.Errorf("want predicate to be called 5 times, got %d", calls)
+	}
+}
+
+func TestDistinctStream(t *testing.T) {
+	got := fn.DistinctStream(fn.From([]int{3, 1, 3, 2, 1})).Collect()
+	want := []int{3, 1, 2}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// keys of a non-comparable element type
+	words := fn.From([][]string{{"a", "b"}, {"c"}, {"d", "e"}})
+	byLen := fn.DistinctFuncStream(words, func(v []string) int { return len(v) }).Collect()
+	if wantLen := [][]string{{"a", "b"}, {"c"}}; !reflect.DeepEqual(wantLen, byLen) {
+		t.Errorf("want %v, got %v", wantLen, byLen)
+	}
+}
+
+func TestStreamTypeChange(t *testing.T) {
+	s := fn.From([]int{1, 2, 3})
+	got := fn.MapStream(s.Map(func(v int) int { return v * 10 }), func(v int) string {
+		return fmt.Sprint(v)
+	}).Collect()
+
+	want := []string{"10", "20", "30"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	sum := fn.ReduceStream(s, func(acc, v int) int { return acc + v }, 0)
+	if sum != 6 {
+		t.Errorf("want %v, got %v", 6, sum)
+	}
+
+	if got := fn.IndexOfStream(s, 3); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+}
+
+func TestStreamChunkFlatten(t *testing.T) {
+	s := fn.From([]int{1, 2, 3, 4, 5})
+	chunks := fn.ChunkStream(s, 2).Collect()
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(want, chunks) {
+		t.Errorf("want %v, got %v", want, chunks)
+	}
+
+	flat := fn.FlattenStream(fn.ChunkStream(s, 2)).Collect()
+	if !reflect.DeepEqual([]int{1, 2, 3, 4, 5}, flat) {
+		t.Errorf("want %v, got %v", []int{1, 2, 3, 4, 5}, flat)
+	}
+}
+
+func TestStreamPartition(t *testing.T) {
+	isEven := func(v int) bool { return v%2 == 0 }
+	evens, odds := fn.From([]int{1, 2, 3, 4, 5}).Partition(isEven)
+	if !reflect.DeepEqual([]int{2, 4}, evens) || !reflect.DeepEqual([]int{1, 3, 5}, odds) {
+		t.Errorf("want %v, %v, got %v, %v", []int{2, 4}, []int{1, 3, 5}, evens, odds)
+	}
+}
+
+func TestStreamRotate(t *testing.T) {
+	s := fn.From([]int{1, 2, 3, 4, 5})
+
+	got := s.RotateLeft(2).Collect()
+	if want := []int{3, 4, 5, 1, 2}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	got = s.RotateRight(2).Collect()
+	if want := []int{4, 5, 1, 2, 3}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	got = s.Reverse().Collect()
+	if want := []int{5, 4, 3, 2, 1}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestStreamMisc(t *testing.T) {
+	s := fn.From([]int{1, 2, 3, 4, 5})
+	isEven := func(v int) bool { return v%2 == 0 }
+
+	if got := s.Drop(2).TakeWhile(func(v int) bool { return v < 5 }).Collect(); !reflect.DeepEqual([]int{3, 4}, got) {
+		t.Errorf("want %v, got %v", []int{3, 4}, got)
+	}
+	if got := s.Count(isEven); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+	if s.All(isEven) || !s.Any(isEven) {
+		t.Error("want All false and Any true")
+	}
+	if got := s.Concat(fn.From([]int{6})).Collect(); len(got) != 6 {
+		t.Errorf("want 6 elements, got %v", got)
+	}
+	if got := s.Shuffle().Collect(); len(got) != 5 {
+		t.Errorf("want 5 elements, got %v", got)
+	}
+
+	zipped := fn.ZipStream(s, s, func(a, b int) int { return a * b }).Collect()
+	if want := []int{1, 4, 9, 16, 25}; !reflect.DeepEqual(want, zipped) {
+		t.Errorf("want %v, got %v", want, zipped)
+	}
+
+	shortest := fn.ZipShortestStream(s, s.Take(2), func(a, b int) int { return a + b }).Collect()
+	if want := []int{2, 4}; !reflect.DeepEqual(want, shortest) {
+		t.Errorf("want %v, got %v", want, shortest)
+	}
+
+	indexed := fn.ZipWithIndexStream(s.Take(2), func(i, v int) int { return i * v }).Collect()
+	if want := []int{0, 2}; !reflect.DeepEqual(want, indexed) {
+		t.Errorf("want %v, got %v", want, indexed)
+	}
+
+	var sum int
+	s.ForEach(func(v int) { sum += v })
+	if sum != 15 {
+		t.Errorf("want %v, got %v", 15, sum)
+	}
+}